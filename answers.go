@@ -0,0 +1,34 @@
+package promptui
+
+import (
+	"encoding/json"
+	"io"
+)
+
+// AnswerSource provides the answers to prompts by name, so that the prompts can be answered without user
+// interaction, for example in scripted runs and tests.
+type AnswerSource interface {
+	// Lookup returns the answer to the prompt with the given name, and whether there is one.
+	Lookup(name string) (string, bool)
+}
+
+// Answers is an AnswerSource answering with the values of the map, by prompt name.
+type Answers map[string]string
+
+// Lookup returns the answer to the prompt with the given name, and whether there is one.
+func (a Answers) Lookup(name string) (string, bool) {
+	answer, ok := a[name]
+	return answer, ok
+}
+
+// ReadAnswers reads the answers of a JSON object mapping prompt names to their answers, such as
+//
+//	{"name": "gopher", "confirm": "y"}
+func ReadAnswers(r io.Reader) (Answers, error) {
+	var answers Answers
+	err := json.NewDecoder(r).Decode(&answers)
+	if err != nil {
+		return nil, err
+	}
+	return answers, nil
+}
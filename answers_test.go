@@ -0,0 +1,103 @@
+package promptui
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestReadAnswers(t *testing.T) {
+	answers, err := ReadAnswers(strings.NewReader(`{"name": "gopher", "confirm": "y"}`))
+	if err != nil {
+		t.Fatalf("Unexpected error %v", err)
+	}
+
+	exp := Answers{"name": "gopher", "confirm": "y"}
+	if !reflect.DeepEqual(answers, exp) {
+		t.Errorf("Expected %v, got %v", exp, answers)
+	}
+
+	_, err = ReadAnswers(strings.NewReader(`["gopher"]`))
+	if err == nil {
+		t.Error("Expected an error for answers which aren't an object")
+	}
+}
+
+func TestPromptAnswers(t *testing.T) {
+	t.Run("when answered", func(t *testing.T) {
+		p := Prompt{
+			Label:   "Name",
+			Name:    "name",
+			Answers: Answers{"name": "gopher"},
+		}
+		out := scriptedPrompt(&p, "")
+
+		value, err := p.Run()
+		if err != nil {
+			t.Fatalf("Unexpected error %v", err)
+		}
+
+		if value != "gopher" {
+			t.Errorf("Expected %q, got %q", "gopher", value)
+		}
+
+		if !strings.Contains(out.String(), "Name") || !strings.Contains(out.String(), "gopher\n") {
+			t.Errorf("Expected the answer to be displayed, got %q", out.String())
+		}
+	})
+
+	t.Run("when the answer is invalid", func(t *testing.T) {
+		p := Prompt{
+			Label:    "Email",
+			Name:     "email",
+			Answers:  Answers{"email": "gopher"},
+			Validate: ValidateEmail(),
+		}
+		scriptedPrompt(&p, "")
+
+		_, err := p.Run()
+		if err == nil {
+			t.Error("Expected an error for the invalid answer")
+		}
+	})
+
+	t.Run("when not answered", func(t *testing.T) {
+		p := Prompt{
+			Label:   "Name",
+			Name:    "name",
+			Answers: Answers{"other": "value"},
+		}
+		scriptedPrompt(&p, "typed\r")
+
+		value, err := p.Run()
+		if err != nil {
+			t.Fatalf("Unexpected error %v", err)
+		}
+
+		if value != "typed" {
+			t.Errorf("Expected %q, got %q", "typed", value)
+		}
+	})
+
+	t.Run("in a form", func(t *testing.T) {
+		f := Form{
+			Questions: []Question{
+				{Name: "name", Prompt: Prompt{Label: "Name"}},
+				{Name: "app", Prompt: Prompt{Label: "App"}},
+			},
+			Answers: Answers{"name": "gopher"},
+		}
+		scriptedPrompt(&f.Questions[0].Prompt, "")
+		scriptedPrompt(&f.Questions[1].Prompt, "app\r")
+
+		answers, err := f.Run()
+		if err != nil {
+			t.Fatalf("Unexpected error %v", err)
+		}
+
+		exp := map[string]string{"name": "gopher", "app": "app"}
+		if !reflect.DeepEqual(answers, exp) {
+			t.Errorf("Expected %v, got %v", exp, answers)
+		}
+	})
+}
@@ -0,0 +1,102 @@
+package promptui
+
+import (
+	"errors"
+	"os"
+	"os/exec"
+	"strings"
+	"unicode"
+)
+
+// ErrNoClipboard is the error returned when the system clipboard can't be accessed because none of the
+// clipboard tools of the platform is installed.
+var ErrNoClipboard = errors.New("no clipboard tool available")
+
+// ClipboardError is the error returned by prompts when the value was entered successfully but couldn't be
+// copied to the clipboard. The value is still returned along with it.
+type ClipboardError struct {
+	Err error
+}
+
+func (e *ClipboardError) Error() string {
+	return "copying to the clipboard: " + e.Err.Error()
+}
+
+// CopyToClipboard writes text to the system clipboard. It uses pbcopy on macOS, clip on Windows and wl-copy,
+// xclip or xsel on the other platforms, whichever is installed first, wl-copy only in a Wayland session. It
+// returns ErrNoClipboard if none is.
+func CopyToClipboard(text string) error {
+	cmd, err := clipboardCommand(copyCommands)
+	if err != nil {
+		return err
+	}
+
+	cmd.Stdin = strings.NewReader(text)
+
+	return cmd.Run()
+}
+
+// PasteFromClipboard returns the text of the system clipboard. It uses pbpaste on macOS, PowerShell on Windows
+// and wl-paste, xclip or xsel on the other platforms, whichever is installed first, wl-paste only in a Wayland
+// session. It returns ErrNoClipboard if none is.
+func PasteFromClipboard() (string, error) {
+	cmd, err := clipboardCommand(pasteCommands)
+	if err != nil {
+		return "", err
+	}
+
+	out, err := cmd.Output()
+	if err != nil {
+		return "", err
+	}
+
+	return string(out), nil
+}
+
+// singleLine makes pasted text fit on a single line: the trailing line breaks are removed, the other line
+// breaks and the tabs are replaced by spaces and the remaining control characters are dropped.
+func singleLine(text string) string {
+	text = strings.TrimRight(text, "\r\n")
+	text = strings.Replace(text, "\r\n", "\n", -1)
+
+	return strings.Map(func(r rune) rune {
+		switch {
+		case r == '\n', r == '\r', r == '\t':
+			return ' '
+		case unicode.IsControl(r):
+			return -1
+		}
+		return r
+	}, text)
+}
+
+// waylandTools are the clipboard tools which only work in a Wayland session, skipped outside of one even if they
+// are installed.
+var waylandTools = map[string]bool{"wl-copy": true, "wl-paste": true}
+
+// clipboardCommand returns the first of the given commands whose tool is installed and usable in the session.
+func clipboardCommand(commands [][]string) (*exec.Cmd, error) {
+	wayland := os.Getenv("WAYLAND_DISPLAY") != ""
+	for _, c := range commands {
+		if waylandTools[c[0]] && !wayland {
+			continue
+		}
+		if path, err := exec.LookPath(c[0]); err == nil {
+			return exec.Command(path, c[1:]...), nil
+		}
+	}
+	return nil, ErrNoClipboard
+}
+
+// copyToClipboard copies the value returned by a prompt if requested, turning a failure into a ClipboardError.
+func copyToClipboard(enabled bool, value string) error {
+	if !enabled {
+		return nil
+	}
+
+	if err := CopyToClipboard(value); err != nil {
+		return &ClipboardError{Err: err}
+	}
+
+	return nil
+}
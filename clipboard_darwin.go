@@ -0,0 +1,9 @@
+package promptui
+
+var copyCommands = [][]string{
+	{"pbcopy"},
+}
+
+var pasteCommands = [][]string{
+	{"pbpaste"},
+}
@@ -0,0 +1,16 @@
+//go:build !darwin && !windows
+// +build !darwin,!windows
+
+package promptui
+
+var copyCommands = [][]string{
+	{"wl-copy"},
+	{"xclip", "-selection", "clipboard"},
+	{"xsel", "--clipboard", "--input"},
+}
+
+var pasteCommands = [][]string{
+	{"wl-paste", "--no-newline"},
+	{"xclip", "-selection", "clipboard", "-o"},
+	{"xsel", "--clipboard", "--output"},
+}
@@ -0,0 +1,250 @@
+package promptui
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"runtime"
+	"strings"
+	"testing"
+	"unicode"
+)
+
+// fakeClipboard replaces the clipboard tools with a shell command writing to a file, returning the file and a
+// function restoring the tools.
+func fakeClipboard(t *testing.T) (string, func()) {
+	if runtime.GOOS == "windows" {
+		t.Skip("the fake clipboard needs a unix shell")
+	}
+
+	dir, err := ioutil.TempDir("", "clipboard")
+	if err != nil {
+		t.Fatalf("Unexpected error %v", err)
+	}
+	file := filepath.Join(dir, "clipboard")
+
+	savedCopy, savedPaste := copyCommands, pasteCommands
+	copyCommands = [][]string{{"promptui-missing-tool"}, {"sh", "-c", "cat > " + file}}
+	pasteCommands = [][]string{{"promptui-missing-tool"}, {"cat", file}}
+
+	return file, func() {
+		copyCommands, pasteCommands = savedCopy, savedPaste
+		os.RemoveAll(dir)
+	}
+}
+
+func readClipboard(t *testing.T, file string) string {
+	b, err := ioutil.ReadFile(file)
+	if err != nil {
+		t.Fatalf("Unexpected error %v", err)
+	}
+	return string(b)
+}
+
+func TestCopyToClipboard(t *testing.T) {
+	file, restore := fakeClipboard(t)
+	defer restore()
+
+	err := CopyToClipboard("s3cr3t")
+	if err != nil {
+		t.Fatalf("Unexpected error %v", err)
+	}
+
+	if got := readClipboard(t, file); got != "s3cr3t" {
+		t.Errorf("Expected %q, got %q", "s3cr3t", got)
+	}
+
+	copyCommands = [][]string{{"promptui-missing-tool"}}
+
+	err = CopyToClipboard("s3cr3t")
+	if err != ErrNoClipboard {
+		t.Errorf("Expected ErrNoClipboard, got %v", err)
+	}
+}
+
+func TestPasteFromClipboardWayland(t *testing.T) {
+	file, restore := fakeClipboard(t)
+	defer restore()
+
+	err := ioutil.WriteFile(file, []byte("x11"), 0600)
+	if err != nil {
+		t.Fatalf("Unexpected error %v", err)
+	}
+
+	savedTools := waylandTools
+	savedDisplay, ok := os.LookupEnv("WAYLAND_DISPLAY")
+	defer func() {
+		waylandTools = savedTools
+		if ok {
+			os.Setenv("WAYLAND_DISPLAY", savedDisplay)
+		} else {
+			os.Unsetenv("WAYLAND_DISPLAY")
+		}
+	}()
+
+	// echo stands for the tool of the Wayland session, which the other one follows
+	waylandTools = map[string]bool{"echo": true}
+	pasteCommands = [][]string{{"echo", "-n", "wayland"}, {"cat", file}}
+
+	tcs := []struct {
+		scenario string
+		display  string
+		expect   string
+	}{
+		{scenario: "in a Wayland session", display: "wayland-0", expect: "wayland"},
+		{scenario: "outside of a Wayland session", expect: "x11"},
+	}
+
+	for _, tc := range tcs {
+		t.Run(tc.scenario, func(t *testing.T) {
+			os.Setenv("WAYLAND_DISPLAY", tc.display)
+
+			text, err := PasteFromClipboard()
+			if err != nil {
+				t.Fatalf("Unexpected error %v", err)
+			}
+
+			if text != tc.expect {
+				t.Errorf("Expected %q, got %q", tc.expect, text)
+			}
+		})
+	}
+}
+
+func TestPromptCopyToClipboard(t *testing.T) {
+	file, restore := fakeClipboard(t)
+	defer restore()
+
+	p := Prompt{Label: "Token", CopyToClipboard: true}
+	scriptedPrompt(&p, "abc\r")
+
+	value, err := p.Run()
+	if err != nil {
+		t.Fatalf("Unexpected error %v", err)
+	}
+
+	if got := readClipboard(t, file); got != value {
+		t.Errorf("Expected %q, got %q", value, got)
+	}
+
+	t.Run("when there is no clipboard", func(t *testing.T) {
+		copyCommands = nil
+
+		p := Prompt{Label: "Token", CopyToClipboard: true}
+		scriptedPrompt(&p, "abc\r")
+
+		value, err := p.Run()
+		if _, ok := err.(*ClipboardError); !ok {
+			t.Errorf("Expected a ClipboardError, got %v", err)
+		}
+
+		if value != "abc" {
+			t.Errorf("Expected %q, got %q", "abc", value)
+		}
+	})
+}
+
+func TestSelectCopyToClipboard(t *testing.T) {
+	file, restore := fakeClipboard(t)
+	defer restore()
+
+	s := Select{
+		Label:           "Select Number",
+		Items:           []string{"Zero", "One"},
+		CopyToClipboard: true,
+	}
+	scriptedSelect(&s, "\x1b[B\r")
+
+	_, value, err := s.Run()
+	if err != nil {
+		t.Fatalf("Unexpected error %v", err)
+	}
+
+	if got := readClipboard(t, file); got != "One" {
+		t.Errorf("Expected %q, got %q", "One", got)
+	}
+
+	if value != "One" {
+		t.Errorf("Expected %q, got %q", "One", value)
+	}
+}
+
+func TestSingleLine(t *testing.T) {
+	tcs := []struct {
+		input  string
+		expect string
+	}{
+		{input: "token", expect: "token"},
+		{input: "token\n", expect: "token"},
+		{input: "token\r\n\r\n", expect: "token"},
+		{input: "one\ntwo\r\nthree", expect: "one two three"},
+		{input: "one\ttwo", expect: "one two"},
+		{input: "bell\a and \x1b[1mescape", expect: "bell and [1mescape"},
+	}
+
+	for _, tc := range tcs {
+		if got := singleLine(tc.input); got != tc.expect {
+			t.Errorf("Expected %q for %q, got %q", tc.expect, tc.input, got)
+		}
+	}
+}
+
+func TestPromptPaste(t *testing.T) {
+	file, restore := fakeClipboard(t)
+	defer restore()
+
+	err := ioutil.WriteFile(file, []byte("/usr/local\nbin\n"), 0600)
+	if err != nil {
+		t.Fatalf("Unexpected error %v", err)
+	}
+
+	tcs := []struct {
+		scenario string
+		prompt   Prompt
+		keys     string
+		expect   string
+	}{
+		{scenario: "paste", keys: "x\x19y\r", expect: "x/usr/local biny"},
+		{scenario: "paste over the default", prompt: Prompt{Default: "old"}, keys: "\x19\r", expect: "/usr/local bin"},
+		{scenario: "paste up to the max length", prompt: Prompt{MaxLength: 6}, keys: "x\x19\r", expect: "x/usr/"},
+		{scenario: "paste the allowed runes", prompt: Prompt{AllowRune: unicode.IsLetter}, keys: "x\x19\r", expect: "xusrlocalbin"},
+		{scenario: "quoted paste key", keys: "\x16\x19\r", expect: "\x19"},
+	}
+
+	for _, tc := range tcs {
+		t.Run(tc.scenario, func(t *testing.T) {
+			p := tc.prompt
+			p.Label = "Path"
+			scriptedPrompt(&p, tc.keys)
+
+			value, err := p.Run()
+			if err != nil {
+				t.Fatalf("Unexpected error %v", err)
+			}
+
+			if value != tc.expect {
+				t.Errorf("Expected %q, got %q", tc.expect, value)
+			}
+		})
+	}
+
+	t.Run("when there is no clipboard", func(t *testing.T) {
+		pasteCommands = nil
+
+		p := Prompt{Label: "Path"}
+		out := scriptedPrompt(&p, "x\x19\r")
+
+		value, err := p.Run()
+		if err != nil {
+			t.Fatalf("Unexpected error %v", err)
+		}
+
+		if value != "x" {
+			t.Errorf("Expected %q, got %q", "x", value)
+		}
+
+		if !strings.Contains(out.String(), "\a") {
+			t.Errorf("Expected the bell to ring, got %q", out.String())
+		}
+	})
+}
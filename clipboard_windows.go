@@ -0,0 +1,9 @@
+package promptui
+
+var copyCommands = [][]string{
+	{"clip"},
+}
+
+var pasteCommands = [][]string{
+	{"powershell", "-NoProfile", "-Command", "Get-Clipboard -Raw"},
+}
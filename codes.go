@@ -58,12 +58,14 @@ const (
 	hideCursor = esc + "?25l"
 	showCursor = esc + "?25h"
 	clearLine  = esc + "2K"
+	moveUp     = esc + "1A"
 )
 
 // FuncMap defines template helpers for the output. It can be extended as a regular map.
 //
 // The functions inside the map link the state, color and background colors strings detected in templates to a Styler
-// function that applies the given style using the corresponding constant.
+// function that applies the given style using the corresponding constant. The field function gives access to the
+// fields of an item, see the Field docs for more info.
 var FuncMap = template.FuncMap{
 	"black":     Styler(FGBlack),
 	"red":       Styler(FGRed),
@@ -85,6 +87,7 @@ var FuncMap = template.FuncMap{
 	"faint":     Styler(FGFaint),
 	"italic":    Styler(FGItalic),
 	"underline": Styler(FGUnderline),
+	"field":     Field,
 }
 
 func upLine(n uint) string {
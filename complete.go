@@ -0,0 +1,68 @@
+package promptui
+
+import (
+	"strings"
+)
+
+// keyTab is the key code the tab key is translated to before reaching readline, which would ring the bell for it
+// otherwise, having no completion of its own.
+const keyTab rune = 0xE001
+
+// TabBehavior sets what a prompt does when the user presses tab.
+type TabBehavior int
+
+const (
+	// TabComplete completes the input with the Completer of the prompt, ringing the terminal bell when there is
+	// nothing to complete or the prompt has no Completer. It is the default.
+	TabComplete TabBehavior = iota
+
+	// TabInsert inserts a tab character into the input, like any other typed character.
+	TabInsert
+
+	// TabNextField enters the input as enter does, which moves on to the next question when the prompt is asked
+	// by a Form.
+	TabNextField
+)
+
+// complete completes the input of the cursor up to the longest common prefix of the candidates of the Completer
+// starting with it. It returns false when there is nothing to complete.
+func (p *Prompt) complete(cur *Cursor) bool {
+	if p.Completer == nil {
+		return false
+	}
+
+	input := cur.Get()
+	if cur.erase {
+		input = ""
+	}
+
+	var prefix []rune
+	var found bool
+	for _, candidate := range p.Completer(input) {
+		if !strings.HasPrefix(candidate, input) {
+			continue
+		}
+		if !found {
+			prefix, found = []rune(candidate), true
+			continue
+		}
+		prefix = commonPrefix(prefix, []rune(candidate))
+	}
+
+	if !found || len(prefix) <= len([]rune(input)) {
+		return false
+	}
+
+	cur.erase = false
+	cur.Replace(string(prefix))
+	return true
+}
+
+// commonPrefix returns the runes a and b start with.
+func commonPrefix(a, b []rune) []rune {
+	n := 0
+	for n < len(a) && n < len(b) && a[n] == b[n] {
+		n++
+	}
+	return a[:n]
+}
@@ -0,0 +1,96 @@
+package promptui
+
+import (
+	"sync"
+	"time"
+)
+
+// keyTick is the key code pushed by a countdown each time the remaining time to display changes. Readline
+// passes it along to the listener as is.
+const keyTick rune = 30
+
+// countdown tracks the time left before a prompt times out. It restarts each time the user presses a key.
+type countdown struct {
+	timeout time.Duration
+
+	mu       sync.Mutex
+	deadline time.Time
+
+	stop chan struct{}
+	once sync.Once
+}
+
+// newCountdown creates a countdown for the given timeout, starting now.
+func newCountdown(timeout time.Duration) *countdown {
+	c := &countdown{timeout: timeout, stop: make(chan struct{})}
+	c.Reset()
+	return c
+}
+
+// Reset restarts the countdown.
+func (c *countdown) Reset() {
+	c.mu.Lock()
+	c.deadline = time.Now().Add(c.timeout)
+	c.mu.Unlock()
+}
+
+func (c *countdown) left() time.Duration {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return time.Until(c.deadline)
+}
+
+// Remaining returns the time left, rounded up to the second.
+func (c *countdown) Remaining() time.Duration {
+	left := c.left()
+	if left <= 0 {
+		return 0
+	}
+	return ((left + time.Second - 1) / time.Second) * time.Second
+}
+
+// Expired reports whether there is no time left.
+func (c *countdown) Expired() bool {
+	return c.left() <= 0
+}
+
+// Run pushes keyTick into the feed each time the remaining time changes, until the countdown is stopped.
+func (c *countdown) Run(feed *keyFeed) {
+	for {
+		wait := c.left() % time.Second
+		if wait <= 0 {
+			wait += time.Second
+		}
+
+		select {
+		case <-time.After(wait):
+			feed.Push(keyTick)
+		case <-c.stop:
+			return
+		}
+	}
+}
+
+// Wait waits until there is no time left, reporting whether it is the case or whether the countdown was stopped
+// first.
+func (c *countdown) Wait() bool {
+	for {
+		left := c.left()
+		if left <= 0 {
+			return true
+		}
+
+		select {
+		case <-time.After(left):
+		case <-c.stop:
+			return false
+		}
+	}
+}
+
+// Stop stops the countdown.
+func (c *countdown) Stop() {
+	c.once.Do(func() {
+		close(c.stop)
+	})
+}
@@ -2,7 +2,11 @@ package promptui
 
 import (
 	"fmt"
+	"io"
 	"strings"
+	"unicode"
+
+	"github.com/chzyer/readline"
 )
 
 // Pointer is A specific type that translates a given set of runes into a given
@@ -14,7 +18,11 @@ func defaultCursor(ignored []rune) []rune {
 }
 
 func blockCursor(input []rune) []rune {
-	return []rune(fmt.Sprintf("\\e[7m%s\\e[0m", string(input)))
+	return []rune(fmt.Sprintf("\x1b[7m%s\x1b[27m", string(input)))
+}
+
+func noCursor(input []rune) []rune {
+	return input
 }
 
 func pipeCursor(input []rune) []rune {
@@ -30,30 +38,108 @@ var (
 	// input.
 	DefaultCursor Pointer = defaultCursor
 	// BlockCursor is a cursor which highlights a character by inverting colors
-	// on it.
+	// on it. Only the inversion is turned off after the character, which keeps
+	// the colors applied around it.
 	BlockCursor Pointer = blockCursor
 	// PipeCursor is a pipe character "|" which appears before the input
 	// character.
 	PipeCursor Pointer = pipeCursor
+	// NoCursor doesn't display any cursor, for programs drawing their own.
+	// The cursor still moves through the input as usual, and the terminal
+	// cursor stays hidden while the prompts run.
+	NoCursor Pointer = noCursor
+)
+
+// WordBoundaryFunc reports whether there is a word boundary between the
+// adjacent runes prev and cur. It defines what a word is for the word-wise
+// movements and deletions of a Cursor. Whitespace is always skipped over
+// between words.
+type WordBoundaryFunc func(prev, cur rune) bool
+
+// SpaceWordBoundary delimits words with whitespace only. It is the default
+// WordBoundaryFunc.
+func SpaceWordBoundary(prev, cur rune) bool {
+	return unicode.IsSpace(prev) != unicode.IsSpace(cur)
+}
+
+// PunctWordBoundary delimits words with whitespace and punctuation, so that
+// runs of punctuation such as the '/' in paths are words of their own.
+func PunctWordBoundary(prev, cur rune) bool {
+	class := func(r rune) int {
+		switch {
+		case unicode.IsSpace(r):
+			return 0
+		case unicode.IsPunct(r), unicode.IsSymbol(r):
+			return 1
+		}
+		return 2
+	}
+	return class(prev) != class(cur)
+}
+
+// CaseTransform sets the case of the runes inserted into a Cursor.
+type CaseTransform int
+
+const (
+	// CaseNone inserts the runes as they are typed. It is the default.
+	CaseNone CaseTransform = iota
+
+	// CaseUpper inserts the runes in upper case.
+	CaseUpper
+
+	// CaseLower inserts the runes in lower case.
+	CaseLower
 )
 
+// apply returns r in the case set by t.
+func (t CaseTransform) apply(r rune) rune {
+	switch t {
+	case CaseUpper:
+		return unicode.ToUpper(r)
+	case CaseLower:
+		return unicode.ToLower(r)
+	}
+	return r
+}
+
 // Cursor tracks the state associated with the movable cursor
 // The strategy is to keep the prompt, input pristine except for requested
 // modifications. The insertion of the cursor happens during a `format` call
 // and we read in new input via an `Update` call
+//
+// A Cursor can be driven without a Prompt by a custom read loop: feed it each
+// key press with Handle, then render it with Format or FormatMask, or write it
+// to any io.Writer with WriteTo.
+//
+// A Cursor isn't safe for concurrent use: all its methods must be called from
+// the same goroutine, or be serialized by the caller. The prompts only use
+// their cursor from one goroutine at a time, readline's included.
 type Cursor struct {
 	// shows where the user inserts/updates text
 	Cursor Pointer
+	// decides where words start and end, SpaceWordBoundary if nil
+	WordBoundary WordBoundaryFunc
+	// sets the case of the inserted runes, as typed if CaseNone
+	CaseTransform CaseTransform
 	// what the user entered, and what we will echo back to them, after
 	// insertion of the cursor and prefixing with the prompt
 	input []rune
 	// Put the cursor before this slice
 	Position int
 	erase    bool
+	// the next key is inserted as is, see KeyQuote
+	quoted bool
+	// the first rune displayed by formatScrolled
+	scroll int
 }
 
 // NewCursor create a new cursor, with the DefaultCursor, the specified input,
 // and position at the end of the specified starting input.
+//
+// With eraseDefault, the starting input is a default placed before the
+// cursor, replaced by the first text typed or cleared by the first deletion.
+// Any movement key pressed first, even one keeping the cursor in place,
+// keeps the default instead for it to be edited in place.
 func NewCursor(startinginput string, pointer Pointer, eraseDefault bool) Cursor {
 	if pointer == nil {
 		pointer = defaultCursor
@@ -73,6 +159,21 @@ func (c *Cursor) String() string {
 		string(c.Cursor([]rune(""))), string(c.input), c.Position)
 }
 
+// State returns a compact representation of the input and the position of the
+// cursor in it, such as "ab|cd" for the cursor between b and d, whatever its
+// Pointer. It is meant for tests to compare the outcome of editing sequences;
+// a pipe in the input isn't escaped.
+func (c *Cursor) State() string {
+	i := c.Position
+	if i < 0 {
+		i = 0
+	}
+	if i > len(c.input) {
+		i = len(c.input)
+	}
+	return string(c.input[:i]) + "|" + string(c.input[i:])
+}
+
 // End is a convenience for c.Place(len(c.input)) so you don't have to know how I
 // indexed.
 func (c *Cursor) End() {
@@ -85,6 +186,20 @@ func (c *Cursor) Start() {
 	c.Place(0)
 }
 
+// AtStart reports whether the cursor is at the start of the input, before its
+// first rune. A Position out of bounds counts as the nearest bound, as it does
+// when the cursor is placed.
+func (c *Cursor) AtStart() bool {
+	return c.Position <= 0
+}
+
+// AtEnd reports whether the cursor is at the end of the input, after its last
+// rune. A Position out of bounds counts as the nearest bound, as it does when
+// the cursor is placed.
+func (c *Cursor) AtEnd() bool {
+	return c.Position >= len(c.input)
+}
+
 // ensures we are in bounds.
 func (c *Cursor) correctPosition() {
 	if c.Position > len(c.input) {
@@ -122,17 +237,321 @@ func (c *Cursor) Format() string {
 	return format(r, c)
 }
 
+// formatScrolled renders the input like Format within the given number of
+// columns, scrolling it horizontally to keep the cursor in view. The parts of
+// the input out of view are replaced with ‹ and ›. The view only moves as much
+// as needed for the cursor, so that it doesn't jump around while typing.
+func (c *Cursor) formatScrolled(width int) string {
+	n := len(c.input)
+
+	// cell is the width of the cursor along with the rune under it, if any
+	cell := func() int {
+		if c.Position < n {
+			return displayWidth(c.Cursor(c.input[c.Position : c.Position+1]))
+		}
+		return displayWidth(c.Cursor([]rune{}))
+	}()
+
+	// fits reports whether the runes from start to end fit, along with the cursor
+	// and the indicators they need
+	fits := func(start, end int) bool {
+		w := displayWidth(c.input[start:end])
+		if start > 0 {
+			w++
+		}
+		if end < n {
+			w++
+		}
+		if c.Position >= start && c.Position < end {
+			w += cell - displayWidth(c.input[c.Position:c.Position+1])
+		} else if c.Position == n && end == n {
+			w += cell
+		}
+		return w <= width
+	}
+
+	if fits(0, n) {
+		c.scroll = 0
+		return c.Format()
+	}
+
+	start := c.scroll
+	if start > c.Position {
+		start = c.Position
+	}
+	end := c.Position + 1
+	if end > n {
+		end = n
+	}
+	for start < c.Position && !fits(start, end) {
+		start++
+	}
+	for end < n && fits(start, end+1) {
+		end++
+	}
+	for start > 0 && fits(start-1, end) {
+		start--
+	}
+	c.scroll = start
+
+	out := make([]rune, 0, end-start+2)
+	if start > 0 {
+		out = append(out, '‹')
+	}
+	out = append(out, c.input[start:c.Position]...)
+	if c.Position < n {
+		out = append(out, c.Cursor(c.input[c.Position:c.Position+1])...)
+		out = append(out, c.input[c.Position+1:end]...)
+	} else {
+		out = append(out, c.Cursor([]rune{})...)
+	}
+	if end < n {
+		out = append(out, '›')
+	}
+	return string(out)
+}
+
+// formatTransform renders the input as transformed by fn, with the cursor
+// placed after the transformed text that precedes it. The ANSI escape sequences
+// added by fn, such as colors, are not counted as characters and are kept
+// around the cursor.
+func (c *Cursor) formatTransform(fn func(string) string) string {
+	display := []rune(fn(string(c.input)))
+	return formatVisible(display, visibleLen([]rune(fn(string(c.input[:c.Position])))), c)
+}
+
+// formatVisible inserts the cursor on the character of a at position i, not
+// counting the ANSI escape sequences, which are left untouched.
+func formatVisible(a []rune, i int, c *Cursor) string {
+	out := make([]rune, 0, len(a))
+	visible := 0
+	for j := 0; j < len(a); {
+		if n := escapeLen(a[j:]); n > 0 {
+			out = append(out, a[j:j+n]...)
+			j += n
+			continue
+		}
+
+		if visible == i {
+			out = append(out, c.Cursor(a[j:j+1])...)
+		} else {
+			out = append(out, a[j])
+		}
+		visible++
+		j++
+	}
+
+	if i >= visible {
+		out = append(out, c.Cursor([]rune{})...)
+	}
+	return string(out)
+}
+
+// visibleLen returns the number of runes of r, not counting the ANSI escape
+// sequences.
+func visibleLen(r []rune) int {
+	n := 0
+	for j := 0; j < len(r); j++ {
+		if l := escapeLen(r[j:]); l > 0 {
+			j += l - 1
+			continue
+		}
+		n++
+	}
+	return n
+}
+
+// displayWidth returns the number of columns taken by r on the terminal, not
+// counting the ANSI escape sequences.
+func displayWidth(r []rune) int {
+	n := 0
+	for j := 0; j < len(r); j++ {
+		if l := escapeLen(r[j:]); l > 0 {
+			j += l - 1
+			continue
+		}
+		n += readline.Runes{}.Width(r[j])
+	}
+	return n
+}
+
+// truncateWidth returns the start of r taking at most width columns on the
+// terminal. The ANSI escape sequences are all kept, so that the styles cut
+// along with the text are still turned off.
+func truncateWidth(r []rune, width int) []rune {
+	out := make([]rune, 0, len(r))
+	n := 0
+	for j := 0; j < len(r); j++ {
+		if l := escapeLen(r[j:]); l > 0 {
+			out = append(out, r[j:j+l]...)
+			j += l - 1
+			continue
+		}
+		n += readline.Runes{}.Width(r[j])
+		if n <= width {
+			out = append(out, r[j])
+		}
+	}
+	return out
+}
+
+// escapeLen returns the length of the ANSI escape sequence r starts with, or
+// zero if it doesn't start with one.
+func escapeLen(r []rune) int {
+	if len(r) < 2 || r[0] != '\x1b' || r[1] != '[' {
+		return 0
+	}
+	for i := 2; i < len(r); i++ {
+		if r[i] >= 0x40 && r[i] <= 0x7e {
+			return i + 1
+		}
+	}
+	return 0
+}
+
 // FormatMask replaces all input runes with the mask rune.
 func (c *Cursor) FormatMask(mask rune) string {
-	if mask == ' ' {
+	return c.formatMask([]rune{mask}, 0, 0, nil)
+}
+
+// FormatMaskReveal replaces the input runes with the mask rune, except for the
+// last revealLast ones, for example to display only the last digits of a card
+// number.
+func (c *Cursor) FormatMaskReveal(mask rune, revealLast int) string {
+	return c.formatMask([]rune{mask}, 0, revealLast, nil)
+}
+
+// FormatMaskRevealFirst replaces the input runes with the mask rune, except for
+// the first revealFirst ones.
+func (c *Cursor) FormatMaskRevealFirst(mask rune, revealFirst int) string {
+	return c.formatMask([]rune{mask}, revealFirst, 0, nil)
+}
+
+// FormatMaskString replaces the input runes with the runes of mask, repeating
+// it as many times as needed. Each input rune is replaced by a single rune of
+// the mask, so the cursor stays in place even when the mask is made of wide
+// characters.
+func (c *Cursor) FormatMaskString(mask string) string {
+	return c.formatMask([]rune(mask), 0, 0, nil)
+}
+
+// formatMask masks the input with the repeated mask runes, except for the
+// first and last runes to reveal, and inserts the cursor. If style is not nil,
+// it is applied to the masked text, including the cursor when it is on a
+// masked rune, and leaves the revealed runes untouched.
+func (c *Cursor) formatMask(mask []rune, first, last int, style func(string) string) string {
+	if len(mask) == 0 || (len(mask) == 1 && mask[0] == ' ') {
 		return format([]rune{}, c)
 	}
 
-	r := make([]rune, len(c.input))
+	r, masked := c.maskInput(mask, first, last)
+	if style == nil {
+		return format(r, c)
+	}
+
+	cells := runeCells(r)
+	if i := c.Position; i < len(r) {
+		cells[i] = string(c.Cursor(r[i : i+1]))
+		return styleMasked(cells, masked, style)
+	}
+	return styleMasked(cells, masked, style) + string(c.Cursor([]rune{}))
+}
+
+// FormatMaskFixed displays width mask runes followed by the cursor, whatever the
+// length of the input and the position of the cursor, so that the length of
+// the input can't be inferred from what is displayed.
+func (c *Cursor) FormatMaskFixed(mask rune, width int) string {
+	return c.formatMaskFixed([]rune{mask}, width, nil)
+}
+
+// formatMaskFixed is FormatMaskFixed with the repeated mask runes. If style is
+// not nil, it is applied to the mask.
+func (c *Cursor) formatMaskFixed(mask []rune, width int, style func(string) string) string {
+	r := fixedMask(mask, width)
+	if style == nil {
+		return string(r) + string(c.Cursor([]rune{}))
+	}
+	return styleRunes(r, style) + string(c.Cursor([]rune{}))
+}
+
+// fixedMask returns width repeated mask runes, or none for a blank mask.
+func fixedMask(mask []rune, width int) []rune {
+	if len(mask) == 0 || (len(mask) == 1 && mask[0] == ' ') {
+		return nil
+	}
+	return maskRunes(width, mask)
+}
+
+// maskInput masks the input with the repeated mask runes, except for the first
+// and last runes to reveal. It also returns which of the runes are masked.
+func (c *Cursor) maskInput(mask []rune, first, last int) ([]rune, []bool) {
+	r := maskRunes(len(c.input), mask)
+	masked := make([]bool, len(r))
 	for i := range r {
-		r[i] = mask
+		if i < first || i >= len(r)-last {
+			r[i] = c.input[i]
+		} else {
+			masked[i] = true
+		}
 	}
-	return format(r, c)
+	return r, masked
+}
+
+// getMask returns the input masked like formatMask does, without the cursor.
+func (c *Cursor) getMask(mask []rune, first, last int, style func(string) string) string {
+	if len(mask) == 0 {
+		return ""
+	}
+
+	r, masked := c.maskInput(mask, first, last)
+	if style == nil {
+		return string(r)
+	}
+	return styleMasked(runeCells(r), masked, style)
+}
+
+func maskRunes(n int, mask []rune) []rune {
+	r := make([]rune, n)
+	for i := range r {
+		r[i] = mask[i%len(mask)]
+	}
+	return r
+}
+
+func styleRunes(r []rune, style func(string) string) string {
+	if len(r) == 0 {
+		return ""
+	}
+	return style(string(r))
+}
+
+// runeCells returns each rune of r as a string of its own.
+func runeCells(r []rune) []string {
+	cells := make([]string, len(r))
+	for i := range r {
+		cells[i] = string(r[i])
+	}
+	return cells
+}
+
+// styleMasked applies style to each sequence of masked cells, each cell being
+// the display of a rune.
+func styleMasked(cells []string, masked []bool, style func(string) string) string {
+	var out strings.Builder
+	for start := 0; start < len(cells); {
+		end := start + 1
+		for end < len(cells) && masked[end] == masked[start] {
+			end++
+		}
+
+		if masked[start] {
+			out.WriteString(style(strings.Join(cells[start:end], "")))
+		} else {
+			out.WriteString(strings.Join(cells[start:end], ""))
+		}
+		start = end
+	}
+	return out.String()
 }
 
 // Update inserts newinput into the input []rune in the appropriate place.
@@ -140,12 +559,36 @@ func (c *Cursor) FormatMask(mask rune) string {
 func (c *Cursor) Update(newinput string) {
 	a := c.input
 	b := []rune(newinput)
+	for j, r := range b {
+		b[j] = c.CaseTransform.apply(r)
+	}
 	i := c.Position
 	a = append(a[:i], append(b, a[i:]...)...)
 	c.input = a
 	c.Move(len(b))
 }
 
+// InsertAt inserts s into the input before the rune at the given position,
+// which is brought within the bounds of the input. Only an insertion before
+// the cursor shifts it, by the length of s, keeping it on the same rune. An
+// insertion at the cursor leaves Position as is, the cursor ending up on the
+// inserted text. Unlike Update, s is inserted as is, regardless of the
+// CaseTransform.
+func (c *Cursor) InsertAt(pos int, s string) {
+	if pos < 0 {
+		pos = 0
+	}
+	if pos > len(c.input) {
+		pos = len(c.input)
+	}
+
+	b := []rune(s)
+	c.input = append(c.input[:pos], append(b, c.input[pos:]...)...)
+	if pos < c.Position {
+		c.Position += len(b)
+	}
+}
+
 // Get returns a copy of the input
 func (c *Cursor) Get() string {
 	return string(c.input)
@@ -156,6 +599,14 @@ func (c *Cursor) GetMask(mask rune) string {
 	return strings.Repeat(string(mask), len(c.input))
 }
 
+// GetMaskString returns the mask repeated to as many runes as the input.
+func (c *Cursor) GetMaskString(mask string) string {
+	if mask == "" {
+		return ""
+	}
+	return string(maskRunes(len(c.input), []rune(mask)))
+}
+
 // Replace replaces the previous input with whatever is specified, and moves the
 // cursor to the end position
 func (c *Cursor) Replace(input string) {
@@ -196,8 +647,119 @@ func (c *Cursor) Backspace() {
 	c.Move(-1)
 }
 
+func (c *Cursor) boundary(prev, cur rune) bool {
+	if c.WordBoundary == nil {
+		return SpaceWordBoundary(prev, cur)
+	}
+	return c.WordBoundary(prev, cur)
+}
+
+// wordStart returns the index of the start of the word before the cursor.
+func (c *Cursor) wordStart() int {
+	a := c.input
+	i := c.Position
+	for i > 0 && unicode.IsSpace(a[i-1]) {
+		i--
+	}
+	if i > 0 {
+		i--
+		for i > 0 && !c.boundary(a[i-1], a[i]) {
+			i--
+		}
+	}
+	return i
+}
+
+// MoveWordBackward moves the cursor to the start of the word before it.
+func (c *Cursor) MoveWordBackward() {
+	c.Place(c.wordStart())
+}
+
+// MoveWordForward moves the cursor to the end of the word after it.
+func (c *Cursor) MoveWordForward() {
+	a := c.input
+	i := c.Position
+	for i < len(a) && unicode.IsSpace(a[i]) {
+		i++
+	}
+	if i < len(a) {
+		i++
+		for i < len(a) && !c.boundary(a[i-1], a[i]) {
+			i++
+		}
+	}
+	c.Place(i)
+}
+
+// DeleteWordBackward removes the word before the cursor, along with the
+// whitespace between it and the cursor.
+func (c *Cursor) DeleteWordBackward() {
+	start := c.wordStart()
+	c.input = append(c.input[:start], c.input[c.Position:]...)
+	c.Place(start)
+}
+
+// wipe overwrites the input with zeros, including any rune left over in its
+// underlying array, and empties it.
+func (c *Cursor) wipe() {
+	full := c.input[:cap(c.input)]
+	for i := range full {
+		full[i] = 0
+	}
+	c.input = c.input[:0]
+	c.Position = 0
+}
+
+// Handle updates the cursor state for a single key press, for read loops that
+// don't go through readline. Printable keys are inserted at the cursor
+// position, while the editing keys understood by Listen move the cursor or
+// delete input. It returns false once the input is complete, when the key is
+// KeyEnter.
+func (c *Cursor) Handle(key rune) bool {
+	var line []rune
+	if unicode.IsPrint(key) {
+		line = []rune{key}
+	}
+	_, _, keepOn := c.Listen(line, 0, key)
+	return keepOn
+}
+
+// WriteTo writes the input with the cursor positioned in it to w, as rendered
+// by Format. It implements io.WriterTo.
+func (c *Cursor) WriteTo(w io.Writer) (int64, error) {
+	n, err := io.WriteString(w, c.Format())
+	return int64(n), err
+}
+
+// listens reports whether Listen does something of the given control key other than inserting it.
+func listens(key rune) bool {
+	switch key {
+	case KeyEnter, KeyBackspace, KeyCtrlH, KeyForward, KeyBackward, KeyLineStart, KeyLineEnd, KeyDeleteWord,
+		KeyWordForward, KeyWordBackward, KeyQuote:
+		return true
+	}
+	return false
+}
+
 // Listen is a readline Listener that updates internal cursor state appropriately.
 func (c *Cursor) Listen(line []rune, pos int, key rune) ([]rune, int, bool) {
+	if c.quoted {
+		c.quoted = false
+		if key > 0 && key != KeyEnter {
+			if c.erase {
+				c.erase = false
+				c.Replace("")
+			}
+			c.Update(string(key))
+			return []rune(c.Get()), c.Position, true
+		}
+	}
+
+	if key == KeyQuote {
+		c.quoted = true
+		return []rune(c.Get()), c.Position, true
+	}
+
 	if line != nil {
 		// no matter what, update our internal representation.
 		c.Update(string(line))
@@ -215,16 +777,36 @@ func (c *Cursor) Listen(line []rune, pos int, key rune) ([]rune, int, bool) {
 		c.Backspace()
 	case KeyForward:
 		// the user wants to edit the default, despite how we set it up. Let
-		// them.
+		// them. The same goes for all the movement keys.
 		c.erase = false
 		c.Move(1)
 	case KeyBackward:
+		c.erase = false
 		c.Move(-1)
-	default:
+	case KeyLineStart:
+		c.erase = false
+		c.Start()
+	case KeyLineEnd:
+		c.erase = false
+		c.End()
+	case KeyDeleteWord:
 		if c.erase {
 			c.erase = false
 			c.Replace("")
-			c.Update(string(key))
+		}
+		c.DeleteWordBackward()
+	case KeyWordForward:
+		c.erase = false
+		c.MoveWordForward()
+	case KeyWordBackward:
+		c.erase = false
+		c.MoveWordBackward()
+	default:
+		// the typed text replaces the default, the other keys leave it as is
+		if c.erase && len(line) > 0 {
+			c.erase = false
+			c.Replace("")
+			c.Update(string(line))
 		}
 	}
 
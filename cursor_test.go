@@ -101,3 +101,340 @@ func TestCursor(t *testing.T) {
 		}
 	})
 }
+
+func TestCursorMask(t *testing.T) {
+	cursor := Cursor{input: []rune("secret"), Cursor: pipeCursor}
+	cursor.Place(2)
+
+	if f := cursor.FormatMask('*'); f != "**|****" {
+		t.Errorf("expected '**|****'; found '%s'", f)
+	}
+
+	if f := cursor.FormatMask(' '); f != "|" {
+		t.Errorf("expected '|'; found '%s'", f)
+	}
+
+	if f := cursor.FormatMaskString("•◦"); f != "•◦|•◦•◦" {
+		t.Errorf("expected '•◦|•◦•◦'; found '%s'", f)
+	}
+
+	if f := cursor.FormatMaskString("＊"); f != "＊＊|＊＊＊＊" {
+		t.Errorf("expected '＊＊|＊＊＊＊'; found '%s'", f)
+	}
+
+	if m := cursor.GetMaskString("ab"); m != "ababab" {
+		t.Errorf("expected 'ababab'; found '%s'", m)
+	}
+
+	style := func(s string) string { return "<" + s + ">" }
+	if f := cursor.formatMask([]rune("*"), 0, 0, style); f != "<**|****>" {
+		t.Errorf("expected '<**|****>'; found '%s'", f)
+	}
+
+	cursor.End()
+	if f := cursor.formatMask([]rune("*"), 0, 0, style); f != "<******>|" {
+		t.Errorf("expected '<******>|'; found '%s'", f)
+	}
+}
+
+func TestCursorMaskReveal(t *testing.T) {
+	cursor := Cursor{input: []rune("41111234"), Cursor: pipeCursor}
+
+	tcs := []struct {
+		position int
+		last     string
+		first    string
+	}{
+		{0, "|****1234", "|41******"},
+		{2, "**|**1234", "41|******"},
+		{3, "***|*1234", "41*|*****"},
+		{4, "****|1234", "41**|****"},
+		{5, "****1|234", "41***|***"},
+		{8, "****1234|", "41******|"},
+	}
+
+	for _, tc := range tcs {
+		cursor.Place(tc.position)
+		if f := cursor.FormatMaskReveal('*', 4); f != tc.last {
+			t.Errorf("at %d: expected '%s'; found '%s'", tc.position, tc.last, f)
+		}
+		if f := cursor.FormatMaskRevealFirst('*', 2); f != tc.first {
+			t.Errorf("at %d: expected '%s'; found '%s'", tc.position, tc.first, f)
+		}
+	}
+
+	cursor = Cursor{input: []rune("123"), Cursor: pipeCursor}
+	cursor.End()
+	if f := cursor.FormatMaskReveal('*', 4); f != "123|" {
+		t.Errorf("expected '123|'; found '%s'", f)
+	}
+
+	style := func(s string) string { return "<" + s + ">" }
+	cursor = Cursor{input: []rune("41111234"), Cursor: pipeCursor}
+	cursor.Place(5)
+	if f := cursor.formatMask([]rune("*"), 1, 2, style); f != "4<****|*>34" {
+		t.Errorf("expected '4<****|*>34'; found '%s'", f)
+	}
+	if m := cursor.getMask([]rune("*"), 1, 2, style); m != "4<*****>34" {
+		t.Errorf("expected '4<*****>34'; found '%s'", m)
+	}
+}
+
+func TestCursorMaskFixed(t *testing.T) {
+	for _, input := range []string{"", "ab", "hunter2345"} {
+		cursor := Cursor{input: []rune(input), Cursor: pipeCursor}
+		for _, position := range []int{0, len(input)} {
+			cursor.Place(position)
+			if f := cursor.FormatMaskFixed('*', 8); f != "********|" {
+				t.Errorf("for %q at %d: expected '********|'; found '%s'", input, position, f)
+			}
+		}
+	}
+
+	cursor := Cursor{input: []rune("abc"), Cursor: pipeCursor}
+	if f := cursor.FormatMaskFixed(' ', 8); f != "|" {
+		t.Errorf("expected '|'; found '%s'", f)
+	}
+
+	style := func(s string) string { return "<" + s + ">" }
+	if f := cursor.formatMaskFixed([]rune("•◦"), 4, style); f != "<•◦•◦>|" {
+		t.Errorf("expected '<•◦•◦>|'; found '%s'", f)
+	}
+}
+
+func TestCursorColors(t *testing.T) {
+	color := Styler(FGCyan)
+	cyan := func(s string) string { return color(s) }
+
+	t.Run("BlockCursor", func(t *testing.T) {
+		cursor := Cursor{input: []rune("abc"), Cursor: BlockCursor}
+		cursor.Place(1)
+
+		exp := cyan("a\x1b[7mb\x1b[27mc")
+		if f := cursor.formatTransform(cyan); f != exp {
+			t.Errorf("expected %q; found %q", exp, f)
+		}
+	})
+
+	t.Run("transformed input", func(t *testing.T) {
+		cursor := Cursor{input: []rune("abc"), Cursor: pipeCursor}
+
+		tcs := []struct {
+			position int
+			expect   string
+		}{
+			{0, cyan("|abc")},
+			{2, cyan("ab|c")},
+			{3, cyan("abc") + "|"},
+		}
+
+		for _, tc := range tcs {
+			cursor.Place(tc.position)
+			if f := cursor.formatTransform(cyan); f != tc.expect {
+				t.Errorf("at %d: expected %q; found %q", tc.position, tc.expect, f)
+			}
+		}
+	})
+
+	t.Run("styled mask", func(t *testing.T) {
+		cursor := Cursor{input: []rune("abc"), Cursor: BlockCursor}
+		cursor.Place(1)
+
+		exp := cyan("*\x1b[7m*\x1b[27m*")
+		if f := cursor.formatMask([]rune("*"), 0, 0, cyan); f != exp {
+			t.Errorf("expected %q; found %q", exp, f)
+		}
+	})
+}
+
+func TestCursorWords(t *testing.T) {
+	tcs := []struct {
+		scenario string
+		boundary WordBoundaryFunc
+		input    string
+		position int
+		action   func(c *Cursor)
+		expect   string
+	}{
+		{"move backward", nil, "git  commit -m", 14, (*Cursor).MoveWordBackward, "git  commit |-m"},
+		{"move backward over spaces", nil, "git  commit -m", 12, (*Cursor).MoveWordBackward, "git  |commit -m"},
+		{"move backward at start", nil, "git", 0, (*Cursor).MoveWordBackward, "|git"},
+		{"move forward", nil, "git  commit -m", 3, (*Cursor).MoveWordForward, "git  commit| -m"},
+		{"move forward at end", nil, "git", 3, (*Cursor).MoveWordForward, "git|"},
+		{"delete", nil, "/usr/local/bin", 14, (*Cursor).DeleteWordBackward, "|"},
+		{"delete with spaces", nil, "git commit  ", 12, (*Cursor).DeleteWordBackward, "git |"},
+		{"delete in the middle", nil, "git commit -m", 10, (*Cursor).DeleteWordBackward, "git | -m"},
+		{"delete path element", PunctWordBoundary, "/usr/local/bin", 14, (*Cursor).DeleteWordBackward, "/usr/local/|"},
+		{"delete punctuation", PunctWordBoundary, "/usr/local/", 11, (*Cursor).DeleteWordBackward, "/usr/local|"},
+		{"custom boundary", func(prev, cur rune) bool { return prev == '/' }, "/usr/lo", 7, (*Cursor).MoveWordBackward, "/usr/|lo"},
+	}
+
+	for _, tc := range tcs {
+		t.Run(tc.scenario, func(t *testing.T) {
+			cursor := Cursor{input: []rune(tc.input), Cursor: pipeCursor, WordBoundary: tc.boundary}
+			cursor.Place(tc.position)
+
+			tc.action(&cursor)
+
+			if f := cursor.Format(); f != tc.expect {
+				t.Errorf("expected '%s'; found '%s'", tc.expect, f)
+			}
+		})
+	}
+}
+
+func TestCursorEraseDefault(t *testing.T) {
+	tcs := []struct {
+		scenario  string
+		keys      []rune
+		transform CaseTransform
+		expect    string
+	}{
+		{"type first", []rune("ab"), CaseNone, "ab|"},
+		{"type first with a case transform", []rune("ab"), CaseUpper, "AB|"},
+		{"delete first", []rune{KeyBackspace, 'a'}, CaseNone, "a|"},
+		{"forward then type", []rune{KeyForward, 'a'}, CaseNone, "ga|opher"},
+		{"backward then type", []rune{KeyBackward, 'a'}, CaseNone, "a|gopher"},
+		{"line end then type", []rune{KeyLineEnd, 'a'}, CaseNone, "gophera|"},
+		{"line start then type", []rune{KeyLineStart, 'a'}, CaseNone, "a|gopher"},
+		{"word backward then type", []rune{KeyWordBackward, 'a'}, CaseNone, "a|gopher"},
+		{"other key then type", []rune{KeyPrev, 'a'}, CaseNone, "a|"},
+	}
+
+	for _, tc := range tcs {
+		t.Run(tc.scenario, func(t *testing.T) {
+			cursor := NewCursor("gopher", nil, true)
+			cursor.CaseTransform = tc.transform
+			for _, key := range tc.keys {
+				cursor.Handle(key)
+			}
+
+			if s := cursor.State(); s != tc.expect {
+				t.Errorf("expected '%s'; found '%s'", tc.expect, s)
+			}
+		})
+	}
+}
+
+func TestCursorBounds(t *testing.T) {
+	tcs := []struct {
+		scenario string
+		input    string
+		position int
+		start    bool
+		end      bool
+	}{
+		{"at start", "ab", 0, true, false},
+		{"in the middle", "ab", 1, false, false},
+		{"at end", "ab", 2, false, true},
+		{"empty", "", 0, true, true},
+		{"before start", "ab", -1, true, false},
+		{"after end", "ab", 3, false, true},
+	}
+
+	for _, tc := range tcs {
+		t.Run(tc.scenario, func(t *testing.T) {
+			cursor := Cursor{input: []rune(tc.input), Cursor: pipeCursor, Position: tc.position}
+
+			if cursor.AtStart() != tc.start {
+				t.Errorf("expected AtStart to be %t", tc.start)
+			}
+
+			if cursor.AtEnd() != tc.end {
+				t.Errorf("expected AtEnd to be %t", tc.end)
+			}
+		})
+	}
+}
+
+func TestCursorInsertAt(t *testing.T) {
+	tcs := []struct {
+		scenario string
+		pos      int
+		expect   string
+	}{
+		{"before the position", 1, "a<>b|cd"},
+		{"at the position", 2, "ab|<>cd"},
+		{"after the position", 3, "ab|c<>d"},
+		{"at start", 0, "<>ab|cd"},
+		{"at end", 4, "ab|cd<>"},
+		{"out of bounds", 9, "ab|cd<>"},
+	}
+
+	for _, tc := range tcs {
+		t.Run(tc.scenario, func(t *testing.T) {
+			cursor := Cursor{input: []rune("abcd"), Position: 2}
+
+			cursor.InsertAt(tc.pos, "<>")
+
+			if s := cursor.State(); s != tc.expect {
+				t.Errorf("expected '%s'; found '%s'", tc.expect, s)
+			}
+		})
+	}
+}
+
+func TestCursorState(t *testing.T) {
+	cursor := NewCursor("gopher", nil, false)
+	if s := cursor.State(); s != "gopher|" {
+		t.Errorf("expected 'gopher|'; found '%s'", s)
+	}
+
+	cursor.Move(-2)
+	cursor.Backspace()
+	cursor.Update("ph")
+	if s := cursor.State(); s != "gopph|er" {
+		t.Errorf("expected 'gopph|er'; found '%s'", s)
+	}
+
+	cursor.Position = 42
+	if s := cursor.State(); s != "goppher|" {
+		t.Errorf("expected 'goppher|'; found '%s'", s)
+	}
+}
+
+func TestCursorWipe(t *testing.T) {
+	cursor := NewCursor("", pipeCursor, false)
+	cursor.Update("secret")
+	cursor.Backspace()
+
+	full := cursor.input[:cap(cursor.input)]
+	cursor.wipe()
+
+	if cursor.Get() != "" || cursor.Position != 0 {
+		t.Errorf("expected an empty cursor, got %s", cursor.String())
+	}
+
+	for i, r := range full {
+		if r != 0 {
+			t.Errorf("expected rune %d to be wiped, got %q", i, r)
+		}
+	}
+}
+
+func TestCursorScroll(t *testing.T) {
+	cursor := NewCursor("abc", pipeCursor, false)
+
+	steps := []struct {
+		scenario string
+		input    string
+		position int
+		expect   string
+	}{
+		{scenario: "fit the input", input: "abc", position: 3, expect: "abc|"},
+		{scenario: "scroll to the end", input: "abcdefghij", position: 10, expect: "‹ghij|"},
+		{scenario: "scroll to the start", input: "abcdefghij", position: 0, expect: "|abcd›"},
+		{scenario: "keep the view while moving", input: "abcdefghij", position: 2, expect: "ab|cd›"},
+		{scenario: "scroll as little as needed", input: "abcdefghij", position: 5, expect: "‹de|f›"},
+		{scenario: "count wide runes twice", input: "日本語の文字", position: 6, expect: "‹文字|"},
+	}
+
+	for _, s := range steps {
+		cursor.Replace(s.input)
+		cursor.Place(s.position)
+
+		if got := cursor.formatScrolled(6); got != s.expect {
+			t.Errorf("%s: expected %q, got %q", s.scenario, s.expect, got)
+		}
+	}
+}
@@ -0,0 +1,428 @@
+package promptui
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"strings"
+	"text/template"
+	"time"
+
+	"github.com/chzyer/readline"
+	"github.com/manifoldco/promptui/screenbuf"
+)
+
+// DefaultDateLayout is the layout used by DatePrompt when none is given.
+const DefaultDateLayout = "2006-01-02"
+
+// DatePrompt represents a date and time to choose field by field. The user moves between the fields of the
+// layout with the left and right arrow keys, changes the focused one with the up and down arrow keys and
+// confirms the date by pressing enter. Only real dates can be chosen: moving from January 31 to February
+// gives February 28 or 29.
+type DatePrompt struct {
+	// Label is the text displayed before the date to direct input. The IconInitial value "?" will be
+	// prepended automatically to the label so it does not need to be added.
+	Label interface{}
+
+	// Default is the initial date. Defaults to the current time. Fields that are not part of the layout
+	// keep the value they have in Default.
+	Default time.Time
+
+	// Layout is the format of the date, following the time package layout syntax. The year (2006), month
+	// (01, Jan, January), day (02, _2), hour (15), minute (04) and second (05) elements become fields the
+	// user can change; everything else is displayed as is. Defaults to DefaultDateLayout.
+	Layout string
+
+	// Min is the earliest date that can be chosen. It is ignored when zero.
+	Min time.Time
+
+	// Max is the latest date that can be chosen. It is ignored when zero.
+	Max time.Time
+
+	// HideHelp sets whether to hide help information.
+	HideHelp bool
+
+	// HideSelected sets whether to hide the text displayed after the date is chosen.
+	HideSelected bool
+
+	// Templates can be used to customize the date prompt output. If nil is passed, the
+	// default templates are used. See the DatePromptTemplates docs for more info.
+	Templates *DatePromptTemplates
+
+	// Keys is the set of keys used to change the date. See the DatePromptKeys docs for more info.
+	Keys *DatePromptKeys
+
+	Stdin  io.ReadCloser
+	Stdout io.WriteCloser
+}
+
+// DatePromptKeys defines the available keys used by a date prompt to change its date.
+type DatePromptKeys struct {
+	// Increase is the key used to increase the focused field. Defaults to up arrow key.
+	Increase Key
+
+	// Decrease is the key used to decrease the focused field. Defaults to down arrow key.
+	Decrease Key
+
+	// Next is the key used to focus the next field. Defaults to right arrow key.
+	Next Key
+
+	// Prev is the key used to focus the previous field. Defaults to left arrow key.
+	Prev Key
+}
+
+// DatePromptTemplates allow a date prompt to be customized following stdlib text/template syntax. Custom
+// state, colors and background color are available for use inside the templates and are documented inside
+// the Variable section of the docs. See the SelectTemplates docs for examples.
+type DatePromptTemplates struct {
+	// Label is a text/template for the label displayed before the date. Defaults to printing the label as it
+	// with the IconInitial.
+	Label string
+
+	// Focused is a text/template for the field of the date currently being changed. It receives the field as
+	// formatted by the layout.
+	Focused string
+
+	// Selected is a text/template for the date once it was chosen. It receives the chosen time.Time.
+	Selected string
+
+	// Help is a text/template for displaying instructions at the top. By default it shows the keys used to
+	// change the date.
+	Help string
+
+	// FuncMap is a map of helper functions that can be used inside of templates according to the text/template
+	// documentation.
+	//
+	// By default, FuncMap contains the color functions used to color the text in templates. If FuncMap
+	// is overridden, the colors functions must be added in the override from promptui.FuncMap to work.
+	FuncMap template.FuncMap
+
+	label    *template.Template
+	focused  *template.Template
+	selected *template.Template
+	help     *template.Template
+}
+
+type dateField int
+
+const (
+	dateNone dateField = iota
+	dateYear
+	dateMonth
+	dateDay
+	dateHour
+	dateMinute
+	dateSecond
+)
+
+// dateElements lists the layout elements that become fields, longest first so that January is not
+// mistaken for Jan.
+var dateElements = []struct {
+	element string
+	field   dateField
+}{
+	{"2006", dateYear},
+	{"January", dateMonth},
+	{"Jan", dateMonth},
+	{"01", dateMonth},
+	{"02", dateDay},
+	{"_2", dateDay},
+	{"15", dateHour},
+	{"04", dateMinute},
+	{"05", dateSecond},
+}
+
+type dateSegment struct {
+	layout string
+	field  dateField
+}
+
+// Run executes the date prompt. It displays the label and the date, letting the user change it until it is
+// confirmed with enter or the prompt is canceled. It returns the chosen date and an error if any occurred
+// during the prompt's execution.
+func (d *DatePrompt) Run() (time.Time, error) {
+	layout := d.Layout
+	if layout == "" {
+		layout = DefaultDateLayout
+	}
+
+	segments := splitDateLayout(layout)
+
+	var fields []int
+	for i, seg := range segments {
+		if seg.field != dateNone {
+			fields = append(fields, i)
+		}
+	}
+
+	if len(fields) == 0 {
+		return time.Time{}, fmt.Errorf("date layout %q has no fields to change", layout)
+	}
+
+	if !d.Min.IsZero() && !d.Max.IsZero() && d.Min.After(d.Max) {
+		return time.Time{}, errors.New("date min must not be after max")
+	}
+
+	d.setKeys()
+
+	err := d.prepareTemplates(layout)
+	if err != nil {
+		return time.Time{}, err
+	}
+
+	c := &readline.Config{
+		Stdin:          translateStdin(d.Stdin),
+		Stdout:         d.Stdout,
+		HistoryLimit:   -1,
+		UniqueEditLine: true,
+	}
+
+	err = c.Init()
+	if err != nil {
+		return time.Time{}, err
+	}
+
+	saveTerminal()
+	defer pushTerminalState(c.Stdout, false)()
+
+	rl, err := readline.NewEx(c)
+	if err != nil {
+		return time.Time{}, err
+	}
+	defer closeOnPanic(rl)
+
+	rl.Write([]byte(hideCursor))
+	sb := screenbuf.New(rl)
+
+	value := d.Default
+	if value.IsZero() {
+		value = time.Now()
+	}
+	value = d.clamp(value)
+
+	focus := 0
+
+	guard := newLineGuard()
+	c.SetListener(guard.listen(func(line []rune, pos int, key rune) ([]rune, int, bool) {
+		defer restoreOnPanic(rl)
+
+		switch {
+		case key == KeyEnter:
+			return nil, 0, true
+		case d.Keys.Increase.Matches(key):
+			value = d.clamp(stepDate(value, segments[fields[focus]].field, 1))
+		case d.Keys.Decrease.Matches(key):
+			value = d.clamp(stepDate(value, segments[fields[focus]].field, -1))
+		case d.Keys.Next.Matches(key):
+			if focus < len(fields)-1 {
+				focus++
+			}
+		case d.Keys.Prev.Matches(key):
+			if focus > 0 {
+				focus--
+			}
+		}
+
+		if !d.HideHelp {
+			sb.Write(render(d.Templates.help, d.Keys))
+		}
+
+		output := render(d.Templates.label, d.Label)
+		for i, seg := range segments {
+			text := value.Format(seg.layout)
+			if i == fields[focus] {
+				output = append(output, render(d.Templates.focused, text)...)
+			} else {
+				output = append(output, text...)
+			}
+		}
+		sb.Write(output)
+
+		sb.Flush()
+
+		return nil, 0, true
+	}))
+
+	_, err = rl.Readline()
+	guard.wait(err)
+
+	if err != nil {
+		switch {
+		case err == readline.ErrInterrupt, err.Error() == "Interrupt":
+			err = ErrInterrupt
+		case err == io.EOF:
+			err = ErrEOF
+		}
+
+		sb.Reset()
+		sb.WriteString("")
+		sb.WriteSequence([]byte(showCursor))
+		sb.Flush()
+		rl.Close()
+		return time.Time{}, err
+	}
+
+	sb.WriteSequence([]byte(showCursor))
+	if d.HideSelected {
+		clearScreen(sb)
+	} else {
+		sb.Reset()
+		sb.Write(render(d.Templates.selected, value))
+		sb.Flush()
+	}
+
+	rl.Close()
+
+	return value, nil
+}
+
+// splitDateLayout splits the layout into the fields the user can change and the text between them.
+func splitDateLayout(layout string) []dateSegment {
+	var segments []dateSegment
+	var literal strings.Builder
+
+	for i := 0; i < len(layout); {
+		matched := false
+		for _, e := range dateElements {
+			if strings.HasPrefix(layout[i:], e.element) {
+				if literal.Len() > 0 {
+					segments = append(segments, dateSegment{layout: literal.String()})
+					literal.Reset()
+				}
+				segments = append(segments, dateSegment{layout: e.element, field: e.field})
+				i += len(e.element)
+				matched = true
+				break
+			}
+		}
+		if !matched {
+			literal.WriteByte(layout[i])
+			i++
+		}
+	}
+
+	if literal.Len() > 0 {
+		segments = append(segments, dateSegment{layout: literal.String()})
+	}
+
+	return segments
+}
+
+// stepDate changes the given field of t by delta. Every field but the year wraps around its range, and the
+// day is kept within the month so that the result is always a real date.
+func stepDate(t time.Time, field dateField, delta int) time.Time {
+	year, month, day := t.Date()
+	hour, min, sec := t.Clock()
+
+	switch field {
+	case dateYear:
+		year += delta
+	case dateMonth:
+		month = time.Month(wrap(int(month)-1+delta, 12) + 1)
+	case dateDay:
+		day = wrap(day-1+delta, daysIn(year, month)) + 1
+	case dateHour:
+		hour = wrap(hour+delta, 24)
+	case dateMinute:
+		min = wrap(min+delta, 60)
+	case dateSecond:
+		sec = wrap(sec+delta, 60)
+	}
+
+	if days := daysIn(year, month); day > days {
+		day = days
+	}
+
+	return time.Date(year, month, day, hour, min, sec, t.Nanosecond(), t.Location())
+}
+
+func daysIn(year int, month time.Month) int {
+	return time.Date(year, month+1, 0, 0, 0, 0, 0, time.UTC).Day()
+}
+
+func wrap(value, n int) int {
+	return ((value % n) + n) % n
+}
+
+func (d *DatePrompt) clamp(t time.Time) time.Time {
+	if !d.Min.IsZero() && t.Before(d.Min) {
+		return d.Min
+	}
+	if !d.Max.IsZero() && t.After(d.Max) {
+		return d.Max
+	}
+	return t
+}
+
+func (d *DatePrompt) setKeys() {
+	if d.Keys != nil {
+		return
+	}
+	d.Keys = &DatePromptKeys{
+		Increase: Key{Code: KeyPrev, Display: KeyPrevDisplay},
+		Decrease: Key{Code: KeyNext, Display: KeyNextDisplay},
+		Next:     Key{Code: KeyForward, Display: KeyForwardDisplay},
+		Prev:     Key{Code: KeyBackward, Display: KeyBackwardDisplay},
+	}
+}
+
+func (d *DatePrompt) prepareTemplates(layout string) error {
+	tpls := d.Templates
+	if tpls == nil {
+		tpls = &DatePromptTemplates{}
+	}
+
+	if tpls.FuncMap == nil {
+		tpls.FuncMap = FuncMap
+	}
+
+	if tpls.Label == "" {
+		tpls.Label = fmt.Sprintf("%s {{.}}: ", IconInitial)
+	}
+
+	tpl, err := template.New("").Funcs(tpls.FuncMap).Parse(tpls.Label)
+	if err != nil {
+		return err
+	}
+
+	tpls.label = tpl
+
+	if tpls.Focused == "" {
+		tpls.Focused = "{{ . | underline }}"
+	}
+
+	tpl, err = template.New("").Funcs(tpls.FuncMap).Parse(tpls.Focused)
+	if err != nil {
+		return err
+	}
+
+	tpls.focused = tpl
+
+	if tpls.Selected == "" {
+		tpls.Selected = fmt.Sprintf(`{{ %q | green }} {{ .Format %q | faint }}`, IconGood, layout)
+	}
+
+	tpl, err = template.New("").Funcs(tpls.FuncMap).Parse(tpls.Selected)
+	if err != nil {
+		return err
+	}
+
+	tpls.selected = tpl
+
+	if tpls.Help == "" {
+		tpls.Help = `{{ "Use the arrow keys to change the date:" | faint }} {{ .Increase.HelpText | faint }} ` +
+			`{{ .Decrease.HelpText | faint }} {{ "and move between fields:" | faint }} ` +
+			`{{ .Prev.HelpText | faint }} {{ .Next.HelpText | faint }}`
+	}
+
+	tpl, err = template.New("").Funcs(tpls.FuncMap).Parse(tpls.Help)
+	if err != nil {
+		return err
+	}
+
+	tpls.help = tpl
+
+	d.Templates = tpls
+
+	return nil
+}
@@ -0,0 +1,138 @@
+package promptui
+
+import (
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestDatePrompt(t *testing.T) {
+	up, down, right, left := "\x1b[A", "\x1b[B", "\x1b[C", "\x1b[D"
+	date := func(year int, month time.Month, day, hour, min int) time.Time {
+		return time.Date(year, month, day, hour, min, 0, 0, time.UTC)
+	}
+
+	tcs := []struct {
+		scenario string
+		prompt   DatePrompt
+		keys     string
+		expect   time.Time
+	}{
+		{
+			scenario: "default date",
+			prompt:   DatePrompt{Default: date(2020, 5, 17, 0, 0)},
+			keys:     "\r",
+			expect:   date(2020, 5, 17, 0, 0),
+		},
+		{
+			scenario: "change the year",
+			prompt:   DatePrompt{Default: date(2020, 5, 17, 0, 0)},
+			keys:     up + up + down + "\r",
+			expect:   date(2021, 5, 17, 0, 0),
+		},
+		{
+			scenario: "change the month and day",
+			prompt:   DatePrompt{Default: date(2020, 5, 17, 0, 0)},
+			keys:     right + down + right + up + up + "\r",
+			expect:   date(2020, 4, 19, 0, 0),
+		},
+		{
+			scenario: "fields wrap around",
+			prompt:   DatePrompt{Default: date(2020, 12, 31, 0, 0)},
+			keys:     right + up + right + up + "\r",
+			expect:   date(2020, 1, 1, 0, 0),
+		},
+		{
+			scenario: "day kept within the month",
+			prompt:   DatePrompt{Default: date(2019, 1, 31, 0, 0)},
+			keys:     right + up + "\r",
+			expect:   date(2019, 2, 28, 0, 0),
+		},
+		{
+			scenario: "focus does not move past the fields",
+			prompt:   DatePrompt{Default: date(2020, 5, 17, 0, 0)},
+			keys:     left + up + right + right + right + up + "\r",
+			expect:   date(2021, 5, 18, 0, 0),
+		},
+		{
+			scenario: "custom layout",
+			prompt:   DatePrompt{Default: date(2020, 5, 17, 10, 30), Layout: "Jan _2 15:04"},
+			keys:     up + right + right + down + right + up + "\r",
+			expect:   date(2020, 6, 17, 9, 31),
+		},
+		{
+			scenario: "bound by max",
+			prompt:   DatePrompt{Default: date(2020, 5, 17, 0, 0), Max: date(2020, 6, 1, 0, 0)},
+			keys:     up + "\r",
+			expect:   date(2020, 6, 1, 0, 0),
+		},
+		{
+			scenario: "bound by min",
+			prompt:   DatePrompt{Default: date(2020, 5, 17, 0, 0), Min: date(2020, 5, 10, 0, 0)},
+			keys:     right + down + "\r",
+			expect:   date(2020, 5, 10, 0, 0),
+		},
+	}
+
+	for _, tc := range tcs {
+		t.Run(tc.scenario, func(t *testing.T) {
+			d := tc.prompt
+			d.Label = "Release"
+			d.Stdin = scriptedStdin(tc.keys)
+			d.Stdout = &nopWriteCloser{}
+
+			value, err := d.Run()
+			if err != nil {
+				t.Fatalf("Unexpected error %v", err)
+			}
+
+			if !value.Equal(tc.expect) {
+				t.Errorf("Expected %v, got %v", tc.expect, value)
+			}
+		})
+	}
+
+	t.Run("when the layout has no fields", func(t *testing.T) {
+		d := DatePrompt{Layout: "Monday"}
+
+		_, err := d.Run()
+		if err == nil {
+			t.Errorf("Expected error, got none")
+		}
+	})
+
+	t.Run("when min is after max", func(t *testing.T) {
+		d := DatePrompt{Min: date(2020, 2, 1, 0, 0), Max: date(2020, 1, 1, 0, 0)}
+
+		_, err := d.Run()
+		if err == nil {
+			t.Errorf("Expected error, got none")
+		}
+	})
+
+	t.Run("renders the focused field", func(t *testing.T) {
+		out := &nopWriteCloser{}
+		d := DatePrompt{
+			Label:   "Release",
+			Default: date(2020, 5, 17, 0, 0),
+			Templates: &DatePromptTemplates{
+				Focused:  "[{{ . }}]",
+				Selected: `{{ .Format "Jan 2, 2006" }}`,
+			},
+			HideHelp: true,
+			Stdin:    scriptedStdin(right + "\r"),
+			Stdout:   out,
+		}
+
+		_, err := d.Run()
+		if err != nil {
+			t.Fatalf("Unexpected error %v", err)
+		}
+
+		for _, exp := range []string{"[2020]-05-17", "2020-[05]-17", "May 17, 2020"} {
+			if !strings.Contains(out.String(), exp) {
+				t.Errorf("Expected output to contain %q, got %q", exp, out.String())
+			}
+		}
+	})
+}
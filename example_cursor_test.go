@@ -0,0 +1,35 @@
+package promptui
+
+import (
+	"fmt"
+	"os"
+)
+
+// This example shows how to drive a cursor with a hand-rolled read loop instead of a prompt. Each key press is
+// fed to the cursor, which is rendered after every one of them.
+func ExampleCursor() {
+	// The keys would usually be read from a terminal in raw mode, for example with ReadKey.
+	keys := []rune{'h', 'e', 'l', 'o', KeyBackward, 'l', KeyEnter}
+
+	cursor := NewCursor("", PipeCursor, false)
+
+	for _, key := range keys {
+		if !cursor.Handle(key) {
+			break
+		}
+
+		cursor.WriteTo(os.Stdout)
+		fmt.Println()
+	}
+
+	fmt.Printf("You entered %s\n", cursor.Get())
+
+	// Output:
+	// h|
+	// he|
+	// hel|
+	// helo|
+	// hel|o
+	// hell|o
+	// You entered hello
+}
@@ -0,0 +1,57 @@
+package promptui
+
+import "reflect"
+
+// Field returns the value of the named field of an item. The item can be a struct, a pointer to a struct or a map
+// with string keys. If the item has no such field, an empty string is returned instead of an error.
+//
+// Field is available inside the templates as the "field" function. It allows a single template to display items
+// of different types inside the same list, even if some of them are missing some of the fields. For example
+//
+//	'{{ field . "Name" | cyan }}'
+func Field(item interface{}, name string) interface{} {
+	v := reflect.ValueOf(item)
+	for v.Kind() == reflect.Ptr || v.Kind() == reflect.Interface {
+		if v.IsNil() {
+			return ""
+		}
+		v = v.Elem()
+	}
+
+	switch v.Kind() {
+	case reflect.Struct:
+		sf, ok := v.Type().FieldByName(name)
+		if !ok {
+			return ""
+		}
+
+		// the field may be promoted from an embedded pointer, which is walked one step at a time so that a nil
+		// one doesn't panic
+		f := v
+		for i, index := range sf.Index {
+			if i > 0 {
+				if f.Kind() == reflect.Ptr && f.IsNil() {
+					return ""
+				}
+				f = reflect.Indirect(f)
+			}
+			f = f.Field(index)
+		}
+		if !f.CanInterface() {
+			return ""
+		}
+		return f.Interface()
+	case reflect.Map:
+		key := v.Type().Key()
+		if key.Kind() != reflect.String {
+			return ""
+		}
+		f := v.MapIndex(reflect.ValueOf(name).Convert(key))
+		if !f.IsValid() {
+			return ""
+		}
+		return f.Interface()
+	default:
+		return ""
+	}
+}
@@ -0,0 +1,52 @@
+package promptui
+
+import "testing"
+
+func TestField(t *testing.T) {
+	type server struct {
+		Name   string
+		Region string
+		port   int
+	}
+
+	type Location struct {
+		Region string
+	}
+
+	type host struct {
+		*Location
+		Name string
+	}
+
+	srv := server{Name: "web", Region: "us-east-1", port: 80}
+
+	tcs := []struct {
+		scenario string
+		item     interface{}
+		field    string
+		expect   interface{}
+	}{
+		{scenario: "struct field", item: srv, field: "Name", expect: "web"},
+		{scenario: "pointer to struct field", item: &srv, field: "Region", expect: "us-east-1"},
+		{scenario: "missing struct field", item: srv, field: "Tags", expect: ""},
+		{scenario: "unexported struct field", item: srv, field: "port", expect: ""},
+		{scenario: "nil pointer", item: (*server)(nil), field: "Name", expect: ""},
+		{scenario: "embedded struct field", item: host{Location: &Location{Region: "eu-west-1"}}, field: "Region",
+			expect: "eu-west-1"},
+		{scenario: "nil embedded struct field", item: host{Name: "web"}, field: "Region", expect: ""},
+		{scenario: "map key", item: map[string]int{"Port": 80}, field: "Port", expect: 80},
+		{scenario: "missing map key", item: map[string]int{"Port": 80}, field: "Name", expect: ""},
+		{scenario: "map without string keys", item: map[int]string{1: "one"}, field: "1", expect: ""},
+		{scenario: "string", item: "web", field: "Name", expect: ""},
+		{scenario: "nil", item: nil, field: "Name", expect: ""},
+	}
+
+	for _, tc := range tcs {
+		t.Run(tc.scenario, func(t *testing.T) {
+			got := Field(tc.item, tc.field)
+			if got != tc.expect {
+				t.Errorf("expected %v, got %v", tc.expect, got)
+			}
+		})
+	}
+}
@@ -0,0 +1,240 @@
+package promptui
+
+import (
+	"context"
+	"errors"
+	"io"
+	"io/ioutil"
+
+	"github.com/chzyer/readline"
+)
+
+// errBack is the error returned when asking a question of a form if the user goes back to the previous question.
+var errBack = errors.New("back")
+
+// Form asks a sequence of questions, one prompt at a time, and collects the answers by the name of their
+// question. It makes it possible to build questionnaires in which a question depends on the previous answers.
+//
+// The questions are asked in the order of Questions, each one once the previous one has been answered. A
+// question only ever sees the answers to the questions before it.
+//
+// The templates of the prompts can display the position of the current question among the questions, which
+// leaves out the questions to skip according to the answers so far. Since later answers can change which
+// questions are skipped, the number of questions can change along the way.
+//
+// The user can go back to the previous question with the Back key to change its answer. That question is then
+// asked again with the previous answer as its default value, and the following questions are asked again once
+// it has been answered, starting from their previous answers.
+type Form struct {
+	// Questions are the questions to ask, in order.
+	Questions []Question
+
+	// KeepSkipped sets whether the questions skipped because of their When function appear in the answers,
+	// with an empty value. By default, they are left out of the answers.
+	KeepSkipped bool
+
+	// Answers is an optional source of answers to the questions, by question name. The questions it has an
+	// answer for are answered without asking the user, unless their prompt has its own Answers. Going back
+	// skips over them.
+	Answers AnswerSource
+
+	// ShowProgress sets whether to display the position of the current question among the questions to answer,
+	// such as "(3/7)", after the label of the prompts using the default templates.
+	ShowProgress bool
+
+	// Keys is the set of keys used by the form. See the FormKeys docs for more info.
+	Keys *FormKeys
+}
+
+// FormKeys defines the available keys used by a form.
+type FormKeys struct {
+	// Back is the key used to go back to the previous question, skipping over the skipped ones. It is looked
+	// for in the keys read from stdin, so it can't be one of the codes readline gives to the arrow keys and
+	// can't be inserted with KeyQuote. Defaults to ctrl-o.
+	Back Key
+}
+
+// Question is a question asked by a Form.
+type Question struct {
+	// Name is the key of the answer to the question in the answers of the form. It should be unique within the
+	// form.
+	Name string
+
+	// When is an optional function deciding from the answers to the previous questions whether to ask the
+	// question. The question is skipped when it returns false, for example to only ask for a region once a
+	// cloud provider has been chosen.
+	When func(answers map[string]string) bool
+
+	// Prompt is the prompt asking the question. Its templates can use the answers function for the answers
+	// to the previous questions, by name. For example
+	// 	'{{ . }} for {{ index answers "project" }}: '
+	Prompt Prompt
+
+	// Default is an optional function returning the default value of the prompt from the answers to the
+	// previous questions. It takes precedence over the Default of the prompt.
+	Default func(answers map[string]string) string
+
+	// Validate is an optional function validating the entered value along with the answers to the previous
+	// questions. It takes precedence over the Validate of the prompt.
+	Validate func(answers map[string]string, input string) error
+
+	// stdin reads the stdin of the prompt across the times the question is asked.
+	stdin *stopReader
+
+	// lines is the number of lines left on the screen by the last answer, to erase them when going back.
+	lines int
+}
+
+// Run asks the questions of the form in order. It returns the answers by question name once they have all been
+// answered. If a prompt fails, it returns the answers given so far along with the error of the prompt.
+func (f *Form) Run() (map[string]string, error) {
+	f.setKeys()
+
+	answers := make(map[string]string, len(f.Questions))
+	given := make(map[string]string, len(f.Questions))
+
+	// answered are the indexes of the questions answered so far, in order, and asked the ones answered by the
+	// user, to go back to them.
+	var answered, asked []int
+
+	for i := 0; i < len(f.Questions); {
+		q := &f.Questions[i]
+		if q.When != nil && !q.When(copyAnswers(answers)) {
+			if f.KeepSkipped {
+				answers[q.Name] = ""
+			}
+			i++
+			continue
+		}
+
+		var back *Key
+		if len(asked) > 0 {
+			back = &f.Keys.Back
+		}
+
+		p := q.Prompt
+		run := &promptRun{
+			answers:      copyAnswers(answers),
+			question:     len(answered) + 1,
+			showProgress: f.ShowProgress,
+		}
+		run.questions = run.question + f.remaining(i+1, answers)
+		if p.Name == "" {
+			p.Name = q.Name
+		}
+		if p.Answers == nil {
+			p.Answers = f.Answers
+		}
+
+		// the questions answered by the source are skipped when going back, like the skipped ones
+		var sourced bool
+		if p.Answers != nil {
+			_, sourced = p.Answers.Lookup(p.Name)
+		}
+
+		previous, ok := given[q.Name]
+		value, err := q.ask(&p, run, previous, ok, back)
+		if err == errBack {
+			// the interrupted prompt leaves an empty line behind
+			NewTerminal(q.output()).ClearLines(1)
+
+			i = asked[len(asked)-1]
+			asked = asked[:len(asked)-1]
+			for len(answered) > 0 && answered[len(answered)-1] >= i {
+				q := &f.Questions[answered[len(answered)-1]]
+				NewTerminal(q.output()).ClearLines(q.lines)
+				answered = answered[:len(answered)-1]
+			}
+			for _, q := range f.Questions[i:] {
+				delete(answers, q.Name)
+			}
+			continue
+		}
+		if err != nil {
+			return answers, err
+		}
+
+		answers[q.Name] = value
+		given[q.Name] = value
+		answered = append(answered, i)
+		if !sourced {
+			asked = append(asked, i)
+		}
+		i++
+	}
+
+	return answers, nil
+}
+
+// remaining returns the number of questions to ask from the given one on, according to the given answers.
+func (f *Form) remaining(from int, answers map[string]string) int {
+	var n int
+	for _, q := range f.Questions[from:] {
+		if q.When == nil || q.When(copyAnswers(answers)) {
+			n++
+		}
+	}
+	return n
+}
+
+func (f *Form) setKeys() {
+	if f.Keys != nil {
+		return
+	}
+	f.Keys = &FormKeys{
+		Back: Key{Code: KeyFormBack, Display: KeyFormBackDisplay},
+	}
+}
+
+// ask runs the given copy of the prompt of the question with the given state, holding the answers to the previous
+// questions. If the question was answered before, the previous answer is its default value. back is the key to go
+// back to the previous question, nil if there is none.
+func (q *Question) ask(p *Prompt, run *promptRun, previous string, answered bool, back *Key) (string, error) {
+	answers := run.answers
+
+	if answered {
+		p.Default = previous
+		p.AllowEdit = true
+	} else if q.Default != nil {
+		p.Default = q.Default(answers)
+	}
+
+	if q.Validate != nil {
+		p.Validate = func(input string) error {
+			return q.Validate(answers, input)
+		}
+	}
+
+	if q.stdin == nil {
+		var stdin io.Reader = readline.Stdin
+		if q.Prompt.Stdin != nil {
+			stdin = q.Prompt.Stdin
+		}
+		q.stdin = &stopReader{stdin: stdin}
+	}
+	q.stdin.watch(back, readline.CharInterrupt)
+	p.Stdin = ioutil.NopCloser(q.stdin)
+
+	value, err := p.run(context.Background(), run)
+	q.lines = run.lines
+	if err == ErrInterrupt && q.stdin.pressed {
+		return "", errBack
+	}
+	return value, err
+}
+
+// output returns where the prompt of the question is displayed.
+func (q *Question) output() io.Writer {
+	if q.Prompt.Stdout != nil {
+		return q.Prompt.Stdout
+	}
+	return readline.Stdout
+}
+
+func copyAnswers(answers map[string]string) map[string]string {
+	c := make(map[string]string, len(answers))
+	for k, v := range answers {
+		c[k] = v
+	}
+	return c
+}
@@ -0,0 +1,290 @@
+package promptui
+
+import (
+	"io"
+	"io/ioutil"
+	"reflect"
+	"strings"
+	"testing"
+	"testing/iotest"
+)
+
+func TestFormAnswers(t *testing.T) {
+	f := Form{
+		Questions: []Question{
+			{Name: "name", Prompt: Prompt{Label: "Name"}},
+			{
+				Name: "app",
+				Prompt: Prompt{
+					Label: "App",
+					Templates: &PromptTemplates{
+						Valid: `{{ . }} for {{ index answers "name" }}: `,
+					},
+				},
+				Default: func(answers map[string]string) string {
+					return answers["name"] + "-app"
+				},
+			},
+		},
+	}
+	scriptedPrompt(&f.Questions[0].Prompt, "gopher\r")
+	out := scriptedPrompt(&f.Questions[1].Prompt, "\r")
+
+	answers, err := f.Run()
+	if err != nil {
+		t.Fatalf("Unexpected error %v", err)
+	}
+
+	if answers["name"] != "gopher" {
+		t.Errorf("Expected name %q, got %q", "gopher", answers["name"])
+	}
+
+	if answers["app"] != "gopher-app" {
+		t.Errorf("Expected app %q, got %q", "gopher-app", answers["app"])
+	}
+
+	if !strings.Contains(out.String(), "App for gopher: ") {
+		t.Errorf("Expected output to contain the previous answer, got %q", out.String())
+	}
+}
+
+func TestFormValidate(t *testing.T) {
+	f := Form{
+		Questions: []Question{
+			{Name: "password", Prompt: Prompt{Label: "Password", Mask: '*'}},
+			{
+				Name:   "confirm",
+				Prompt: Prompt{Label: "Confirm", Mask: '*'},
+				Validate: func(answers map[string]string, input string) error {
+					if input != answers["password"] {
+						return ErrAbort
+					}
+					return nil
+				},
+			},
+		},
+	}
+	scriptedPrompt(&f.Questions[0].Prompt, "secret\r")
+	scriptedPrompt(&f.Questions[1].Prompt, "secrte\r\b\b\bret\r")
+
+	answers, err := f.Run()
+	if err != nil {
+		t.Fatalf("Unexpected error %v", err)
+	}
+
+	if answers["confirm"] != "secret" {
+		t.Errorf("Expected confirm %q, got %q", "secret", answers["confirm"])
+	}
+}
+
+func TestFormWhen(t *testing.T) {
+	newForm := func(provider string) *Form {
+		f := &Form{
+			Questions: []Question{
+				{Name: "provider", Prompt: Prompt{Label: "Provider"}},
+				{
+					Name:   "region",
+					Prompt: Prompt{Label: "AWS region"},
+					When: func(answers map[string]string) bool {
+						return answers["provider"] == "aws"
+					},
+				},
+				{Name: "name", Prompt: Prompt{Label: "Name"}},
+			},
+		}
+		scriptedPrompt(&f.Questions[0].Prompt, provider+"\r")
+		scriptedPrompt(&f.Questions[1].Prompt, "us-east-1\r")
+		scriptedPrompt(&f.Questions[2].Prompt, "gopher\r")
+		return f
+	}
+
+	t.Run("when asked", func(t *testing.T) {
+		answers, err := newForm("aws").Run()
+		if err != nil {
+			t.Fatalf("Unexpected error %v", err)
+		}
+
+		if answers["region"] != "us-east-1" {
+			t.Errorf("Expected region %q, got %q", "us-east-1", answers["region"])
+		}
+	})
+
+	t.Run("when skipped", func(t *testing.T) {
+		answers, err := newForm("gcp").Run()
+		if err != nil {
+			t.Fatalf("Unexpected error %v", err)
+		}
+
+		if _, ok := answers["region"]; ok {
+			t.Errorf("Expected no region, got %q", answers["region"])
+		}
+
+		if answers["name"] != "gopher" {
+			t.Errorf("Expected name %q, got %q", "gopher", answers["name"])
+		}
+	})
+
+	t.Run("when keeping skipped questions", func(t *testing.T) {
+		f := newForm("gcp")
+		f.KeepSkipped = true
+
+		answers, err := f.Run()
+		if err != nil {
+			t.Fatalf("Unexpected error %v", err)
+		}
+
+		if region, ok := answers["region"]; !ok || region != "" {
+			t.Errorf("Expected an empty region, got %q", region)
+		}
+	})
+}
+
+func TestFormTabNextField(t *testing.T) {
+	f := Form{
+		Questions: []Question{
+			{Name: "name", Prompt: Prompt{Label: "Name", TabBehavior: TabNextField}},
+			{Name: "app", Prompt: Prompt{Label: "App"}},
+		},
+	}
+	scriptedPrompt(&f.Questions[0].Prompt, "gopher\t")
+	scriptedPrompt(&f.Questions[1].Prompt, "web\r")
+
+	answers, err := f.Run()
+	if err != nil {
+		t.Fatalf("Unexpected error %v", err)
+	}
+
+	exp := map[string]string{"name": "gopher", "app": "web"}
+	if !reflect.DeepEqual(answers, exp) {
+		t.Errorf("Expected answers %v, got %v", exp, answers)
+	}
+}
+
+// scriptedOneByOne sets up the prompt to read the given keys one at a time, like a terminal does, so that the keys
+// following an enter are left to the next time it is run.
+func scriptedOneByOne(p *Prompt, keys string) *nopWriteCloser {
+	out := &nopWriteCloser{}
+	p.Stdin = ioutil.NopCloser(io.MultiReader(iotest.OneByteReader(strings.NewReader(keys)), idleReader{}))
+	p.Stdout = out
+	return out
+}
+
+func TestFormBack(t *testing.T) {
+	t.Run("to the previous question", func(t *testing.T) {
+		f := Form{
+			Questions: []Question{
+				{Name: "name", Prompt: Prompt{Label: "Name"}},
+				{Name: "app", Prompt: Prompt{Label: "App"}},
+			},
+		}
+		out := scriptedOneByOne(&f.Questions[0].Prompt, "gopher\r!\r")
+		scriptedPrompt(&f.Questions[1].Prompt, "\x0fapp\r")
+
+		answers, err := f.Run()
+		if err != nil {
+			t.Fatalf("Unexpected error %v", err)
+		}
+
+		if answers["name"] != "gopher!" {
+			t.Errorf("Expected name %q, got %q", "gopher!", answers["name"])
+		}
+
+		if answers["app"] != "app" {
+			t.Errorf("Expected app %q, got %q", "app", answers["app"])
+		}
+
+		if !strings.Contains(out.String(), moveUp+clearLine) {
+			t.Errorf("Expected the first answer to be erased, got %q", out.String())
+		}
+	})
+
+	t.Run("over skipped questions", func(t *testing.T) {
+		f := Form{
+			Questions: []Question{
+				{Name: "provider", Prompt: Prompt{Label: "Provider"}},
+				{
+					Name:   "region",
+					Prompt: Prompt{Label: "AWS region"},
+					When: func(answers map[string]string) bool {
+						return answers["provider"] == "aws"
+					},
+				},
+				{Name: "name", Prompt: Prompt{Label: "Name"}},
+			},
+			Keys: &FormKeys{Back: Key{Code: KeyQuote}},
+		}
+		scriptedOneByOne(&f.Questions[0].Prompt, "gcp\r\b\b\baws\r")
+		scriptedPrompt(&f.Questions[1].Prompt, "us-east-1\r")
+		scriptedPrompt(&f.Questions[2].Prompt, "\x16gopher\r")
+
+		answers, err := f.Run()
+		if err != nil {
+			t.Fatalf("Unexpected error %v", err)
+		}
+
+		exp := map[string]string{"provider": "aws", "region": "us-east-1", "name": "gopher"}
+		if !reflect.DeepEqual(answers, exp) {
+			t.Errorf("Expected %v, got %v", exp, answers)
+		}
+	})
+}
+
+func TestFormProgress(t *testing.T) {
+	t.Run("in templates", func(t *testing.T) {
+		tpls := &PromptTemplates{
+			Prompt: "{{ . }} {{ question }}/{{ questions }}: ",
+			Valid:  "{{ . }} {{ question }}/{{ questions }}: ",
+		}
+		f := Form{
+			Questions: []Question{
+				{Name: "provider", Prompt: Prompt{Label: "Provider", Templates: tpls}},
+				{
+					Name:   "region",
+					Prompt: Prompt{Label: "AWS region", Templates: tpls},
+					When: func(answers map[string]string) bool {
+						return answers["provider"] == "aws"
+					},
+				},
+				{Name: "name", Prompt: Prompt{Label: "Name", Templates: tpls}},
+			},
+		}
+		first := scriptedPrompt(&f.Questions[0].Prompt, "gcp\r")
+		scriptedPrompt(&f.Questions[1].Prompt, "us-east-1\r")
+		last := scriptedPrompt(&f.Questions[2].Prompt, "gopher\r")
+
+		_, err := f.Run()
+		if err != nil {
+			t.Fatalf("Unexpected error %v", err)
+		}
+
+		if !strings.Contains(first.String(), "Provider 1/2: ") {
+			t.Errorf("Expected output to contain the progress, got %q", first.String())
+		}
+
+		if !strings.Contains(last.String(), "Name 2/2: ") {
+			t.Errorf("Expected output to contain the progress, got %q", last.String())
+		}
+	})
+
+	t.Run("when shown by the form", func(t *testing.T) {
+		f := Form{
+			Questions: []Question{
+				{Name: "name", Prompt: Prompt{Label: "Name"}},
+				{Name: "app", Prompt: Prompt{Label: "App"}},
+			},
+			ShowProgress: true,
+		}
+		scriptedPrompt(&f.Questions[0].Prompt, "gopher\r")
+		out := scriptedPrompt(&f.Questions[1].Prompt, "app\r")
+
+		_, err := f.Run()
+		if err != nil {
+			t.Fatalf("Unexpected error %v", err)
+		}
+
+		exp := Styler(FGFaint)("(2/2)")
+		if !strings.Contains(out.String(), exp) {
+			t.Errorf("Expected output to contain %q, got %q", exp, out.String())
+		}
+	})
+}
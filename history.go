@@ -0,0 +1,200 @@
+package promptui
+
+import (
+	"bytes"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"strings"
+)
+
+// keyHistorySearch is the key code KeyHistorySearch is translated to before reaching readline, which would start
+// a search of its own otherwise.
+const keyHistorySearch rune = 31
+
+// History holds the values entered in prompts, from the oldest to the most recent. It can be shared with shells
+// and other programs through the history files of bash and readline, which hold one entry per line.
+type History struct {
+	// IgnoreDups sets whether to ignore the entries added right after the same entry, like HISTCONTROL=ignoredups
+	// does in bash.
+	IgnoreDups bool
+
+	// MaxSize is the maximum number of entries of the history. Once it is reached, the oldest entries are
+	// evicted to make room for the new ones. Zero means no maximum.
+	MaxSize int
+
+	entries []string
+}
+
+// Add appends the given entry to the history, as the most recent one. Empty entries are ignored.
+func (h *History) Add(entry string) {
+	if entry == "" {
+		return
+	}
+	if n := len(h.entries); h.IgnoreDups && n > 0 && h.entries[n-1] == entry {
+		return
+	}
+	h.entries = append(h.entries, entry)
+	h.evict()
+}
+
+// evict removes the oldest entries beyond the MaxSize of the history.
+func (h *History) evict() {
+	if h.MaxSize > 0 && len(h.entries) > h.MaxSize {
+		h.entries = h.entries[len(h.entries)-h.MaxSize:]
+	}
+}
+
+// Entries returns the entries of the history, from the oldest to the most recent.
+func (h *History) Entries() []string {
+	return append([]string(nil), h.entries...)
+}
+
+// LoadHistoryFile appends the entries of the given history file to the history. The file holds an entry per line,
+// from the oldest to the most recent, like the bash and readline history files. Empty lines, the timestamp comments
+// written by bash and the consecutive duplicates are skipped. Only the most recent entries are kept when the file
+// holds more than MaxSize. A missing file isn't an error and leaves the history as is, so that the file can be
+// created later on by SaveHistoryFile.
+func (h *History) LoadHistoryFile(path string) error {
+	data, err := ioutil.ReadFile(path)
+	if os.IsNotExist(err) {
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+
+	for _, line := range strings.Split(string(data), "\n") {
+		line = strings.TrimSuffix(line, "\r")
+		if line == "" || isHistoryTimestamp(line) {
+			continue
+		}
+		if n := len(h.entries); n > 0 && h.entries[n-1] == line {
+			continue
+		}
+		h.entries = append(h.entries, line)
+	}
+	h.evict()
+	return nil
+}
+
+// SaveHistoryFile writes the entries of the history to the given file, one per line from the oldest to the most
+// recent, replacing its content. Consecutive duplicates are written once. The file is created if needed, readable
+// by its owner only.
+func (h *History) SaveHistoryFile(path string) error {
+	var buf bytes.Buffer
+	for i, entry := range h.entries {
+		if i > 0 && entry == h.entries[i-1] {
+			continue
+		}
+		buf.WriteString(entry)
+		buf.WriteByte('\n')
+	}
+	return ioutil.WriteFile(path, buf.Bytes(), 0600)
+}
+
+// isHistoryTimestamp reports whether the line of a history file is a timestamp comment, written by bash before the
+// entries when HISTTIMEFORMAT is set.
+func isHistoryTimestamp(line string) bool {
+	if len(line) < 2 || line[0] != '#' {
+		return false
+	}
+	for _, c := range line[1:] {
+		if c < '0' || c > '9' {
+			return false
+		}
+	}
+	return true
+}
+
+// historySearch is a reverse incremental search through the entries of a history, like the one of readline. The
+// query is looked for from the most recent entry to the oldest, narrowing down as the user types it.
+type historySearch struct {
+	entries []string
+	query   []rune
+
+	// match is the index of the entry matching the query, -1 until one is found.
+	match int
+
+	// failed is set when no entry older than the current match holds the query.
+	failed bool
+
+	// original is the input being edited when the search started, restored if it is cancelled.
+	original string
+}
+
+// newHistorySearch starts a search through the entries of the given history, from the original input.
+func newHistorySearch(h *History, original string) *historySearch {
+	return &historySearch{entries: h.Entries(), match: -1, original: original}
+}
+
+// find looks for the query in the entries from the given one down to the oldest. The current match is kept when
+// none holds it.
+func (s *historySearch) find(from int) {
+	s.failed = false
+	if len(s.query) == 0 {
+		s.match = -1
+		return
+	}
+
+	query := string(s.query)
+	for i := from; i >= 0; i-- {
+		if strings.Contains(s.entries[i], query) {
+			s.match = i
+			return
+		}
+	}
+	s.failed = true
+}
+
+// Type appends the given rune to the query, keeping the current match while it still holds the query.
+func (s *historySearch) Type(r rune) {
+	s.query = append(s.query, r)
+	if s.match < 0 {
+		s.find(len(s.entries) - 1)
+	} else {
+		s.find(s.match)
+	}
+}
+
+// Backspace removes the last rune of the query, searching it again from the most recent entry.
+func (s *historySearch) Backspace() {
+	if len(s.query) > 0 {
+		s.query = s.query[:len(s.query)-1]
+	}
+	s.find(len(s.entries) - 1)
+}
+
+// Next moves to the next older entry matching the query.
+func (s *historySearch) Next() {
+	if s.match < 0 {
+		s.find(len(s.entries) - 1)
+	} else {
+		s.find(s.match - 1)
+	}
+}
+
+// Value returns the matching entry, or the original input while there is no match.
+func (s *historySearch) Value() string {
+	if s.match < 0 {
+		return s.original
+	}
+	return s.entries[s.match]
+}
+
+// Format renders the search like readline, the query followed by the matching entry, with the cursor at the
+// start of the query within it, if the entry still holds the query.
+func (s *historySearch) Format(pointer Pointer) string {
+	label := "(reverse-i-search)"
+	if s.failed {
+		label = "(failed reverse-i-search)"
+	}
+
+	value := s.Value()
+	cur := NewCursor(value, pointer, false)
+	if i := strings.Index(value, string(s.query)); s.match >= 0 && i >= 0 {
+		cur.Place(len([]rune(value[:i])))
+	}
+
+	return fmt.Sprintf("%s`%s': %s", label, string(s.query), cur.Format())
+}
@@ -0,0 +1,143 @@
+package promptui
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+)
+
+// historyDir creates a temporary directory for history files.
+func historyDir(t *testing.T) (string, func()) {
+	dir, err := ioutil.TempDir("", "promptui")
+	if err != nil {
+		t.Fatalf("Unexpected error %v", err)
+	}
+	return dir, func() { os.RemoveAll(dir) }
+}
+
+func TestHistoryLoadFile(t *testing.T) {
+	dir, restore := historyDir(t)
+	defer restore()
+
+	t.Run("when the file is missing", func(t *testing.T) {
+		var h History
+		h.Add("ls")
+
+		err := h.LoadHistoryFile(filepath.Join(dir, "missing"))
+		if err != nil {
+			t.Fatalf("Unexpected error %v", err)
+		}
+
+		if exp := []string{"ls"}; !reflect.DeepEqual(h.Entries(), exp) {
+			t.Errorf("Expected %q, got %q", exp, h.Entries())
+		}
+	})
+
+	t.Run("with a bash history file", func(t *testing.T) {
+		path := filepath.Join(dir, ".bash_history")
+		data := "ls\n#1612345678\ncd /tmp\ncd /tmp\n\ngit status\r\nls\n"
+		if err := ioutil.WriteFile(path, []byte(data), 0600); err != nil {
+			t.Fatalf("Unexpected error %v", err)
+		}
+
+		var h History
+		err := h.LoadHistoryFile(path)
+		if err != nil {
+			t.Fatalf("Unexpected error %v", err)
+		}
+
+		if exp := []string{"ls", "cd /tmp", "git status", "ls"}; !reflect.DeepEqual(h.Entries(), exp) {
+			t.Errorf("Expected %q, got %q", exp, h.Entries())
+		}
+	})
+}
+
+func TestHistorySaveFile(t *testing.T) {
+	dir, restore := historyDir(t)
+	defer restore()
+
+	var h History
+	for _, entry := range []string{"ls", "ls", "", "cd /tmp", "ls"} {
+		h.Add(entry)
+	}
+
+	path := filepath.Join(dir, "history")
+	err := h.SaveHistoryFile(path)
+	if err != nil {
+		t.Fatalf("Unexpected error %v", err)
+	}
+
+	data, err := ioutil.ReadFile(path)
+	if err != nil {
+		t.Fatalf("Unexpected error %v", err)
+	}
+
+	if exp := "ls\ncd /tmp\nls\n"; string(data) != exp {
+		t.Errorf("Expected %q, got %q", exp, data)
+	}
+
+	var loaded History
+	err = loaded.LoadHistoryFile(path)
+	if err != nil {
+		t.Fatalf("Unexpected error %v", err)
+	}
+
+	if exp := []string{"ls", "cd /tmp", "ls"}; !reflect.DeepEqual(loaded.Entries(), exp) {
+		t.Errorf("Expected %q, got %q", exp, loaded.Entries())
+	}
+}
+
+func TestHistoryIgnoreDups(t *testing.T) {
+	tcs := []struct {
+		scenario   string
+		ignoreDups bool
+		expect     []string
+	}{
+		{"when keeping duplicates", false, []string{"ls", "ls", "cd", "ls"}},
+		{"when ignoring duplicates", true, []string{"ls", "cd", "ls"}},
+	}
+
+	for _, tc := range tcs {
+		t.Run(tc.scenario, func(t *testing.T) {
+			h := History{IgnoreDups: tc.ignoreDups}
+			for _, entry := range []string{"ls", "ls", "cd", "ls"} {
+				h.Add(entry)
+			}
+
+			if !reflect.DeepEqual(h.Entries(), tc.expect) {
+				t.Errorf("Expected %q, got %q", tc.expect, h.Entries())
+			}
+		})
+	}
+}
+
+func TestHistoryMaxSize(t *testing.T) {
+	h := History{MaxSize: 3}
+	for _, entry := range []string{"a", "b", "c", "d", "e"} {
+		h.Add(entry)
+	}
+
+	if exp := []string{"c", "d", "e"}; !reflect.DeepEqual(h.Entries(), exp) {
+		t.Errorf("Expected %q, got %q", exp, h.Entries())
+	}
+
+	dir, restore := historyDir(t)
+	defer restore()
+
+	path := filepath.Join(dir, "history")
+	if err := ioutil.WriteFile(path, []byte("1\n2\n3\n4\n"), 0600); err != nil {
+		t.Fatalf("Unexpected error %v", err)
+	}
+
+	loaded := History{MaxSize: 2}
+	err := loaded.LoadHistoryFile(path)
+	if err != nil {
+		t.Fatalf("Unexpected error %v", err)
+	}
+
+	if exp := []string{"3", "4"}; !reflect.DeepEqual(loaded.Entries(), exp) {
+		t.Errorf("Expected %q, got %q", exp, loaded.Entries())
+	}
+}
@@ -6,7 +6,8 @@ import "github.com/chzyer/readline"
 // to specific actions of promptui in prompt mode and can be remapped if necessary.
 var (
 	// KeyEnter is the default key for submission/selection.
-	KeyEnter rune = readline.CharEnter
+	KeyEnter        rune = readline.CharEnter
+	KeyEnterDisplay      = "↵"
 
 	// KeyCtrlH is the key for deleting input text.
 	KeyCtrlH rune = readline.CharCtrlH
@@ -26,4 +27,76 @@ var (
 	// KeyForward is the default key to page down during selection.
 	KeyForward        rune = readline.CharForward
 	KeyForwardDisplay      = "→"
+
+	// KeyLineStart is the default key to move the cursor to the start of the input (ctrl-a).
+	KeyLineStart rune = readline.CharLineStart
+
+	// KeyLineEnd is the default key to move the cursor to the end of the input (ctrl-e).
+	KeyLineEnd rune = readline.CharLineEnd
+
+	// KeyWordBackward is the default key to move the cursor to the start of the previous word (alt-b).
+	KeyWordBackward rune = readline.MetaBackward
+
+	// KeyWordForward is the default key to move the cursor to the end of the next word (alt-f).
+	KeyWordForward rune = readline.MetaForward
+
+	// KeyDeleteWord is the default key to delete the word before the cursor (ctrl-w).
+	KeyDeleteWord rune = readline.CharCtrlW
+
+	// KeyMultiSelectAll is the default key to check all the items of a multi select.
+	KeyMultiSelectAll        rune = readline.CharLineStart // ctrl-a
+	KeyMultiSelectAllDisplay      = "^A"
+
+	// KeyMultiSelectClear is the default key to uncheck all the items of a multi select.
+	KeyMultiSelectClear        rune = 24 // ctrl-x
+	KeyMultiSelectClearDisplay      = "^X"
+
+	// KeyPaste is the default key to insert the content of the system clipboard in a prompt (ctrl-y). The
+	// content is made to fit on a single line, and the terminal bell rings instead if the clipboard can't be
+	// read.
+	KeyPaste rune = readline.CharCtrlY
+
+	// KeyQuote is the default key to insert the next key literally in a prompt instead of interpreting
+	// it (ctrl-v). This allows entering tab and the control keys, such as ctrl-a, as part of the input.
+	// Enter, escape, ctrl-c, ctrl-d, ctrl-r, ctrl-s and ctrl-z are handled by the terminal before
+	// reaching the prompt and can't be inserted. Control characters are echoed to the terminal as is,
+	// use a Mask to avoid it.
+	KeyQuote rune = 22 // ctrl-v
+
+	// KeyHistorySearch is the default key to start a reverse search through the History of a prompt, or to move to
+	// the next older match once searching (ctrl-r).
+	KeyHistorySearch rune = readline.CharBckSearch
+
+	// KeyHistoryCancel is the default key to cancel a search through the History of a prompt, restoring the input
+	// as it was before the search (ctrl-g).
+	KeyHistoryCancel rune = readline.CharBell
+
+	// KeyFormBack is the default key to go back to the previous question of a form.
+	KeyFormBack        rune = 15 // ctrl-o
+	KeyFormBackDisplay      = "^O"
+
+	// KeyLayout is the default key to switch between the compact and detailed layouts of a select.
+	KeyLayout        rune = 20 // ctrl-t
+	KeyLayoutDisplay      = "^T"
+
+	// KeyTag is the default key to cycle through the tags filtering a select.
+	KeyTag        rune = readline.CharTab
+	KeyTagDisplay      = "⇥"
+
+	// KeyDelete is the default key to delete the active item of a select having an OnDelete function, sent by
+	// both the delete key and ctrl-d.
+	KeyDelete        rune = readline.CharDelete
+	KeyDeleteDisplay      = "del"
+
+	// KeyMoveUp and KeyMoveDown are the default keys to move the active item of a select having an OnReorder
+	// function up and down (alt-up and alt-down). Terminals send escape sequences for these keys which readline
+	// reads as the plain arrows, so the select translates them into these codes, which no key types.
+	KeyMoveUp          rune = 0xE002
+	KeyMoveUpDisplay        = "alt-↑"
+	KeyMoveDown        rune = 0xE003
+	KeyMoveDownDisplay      = "alt-↓"
+
+	// KeyNone is the default key to choose none of the items during selection.
+	KeyNone        rune = 24 // ctrl-x
+	KeyNoneDisplay      = "^X"
 )
@@ -0,0 +1,178 @@
+package promptui
+
+import (
+	"bytes"
+	"io"
+	"sync"
+)
+
+// keyFeed wraps the stdin handed to readline so that keys can be pushed into the input stream
+// alongside the ones typed by the user. This lets a prompt react to events that do not come from
+// the keyboard, such as confirming a select once a search narrows down to a single item.
+type keyFeed struct {
+	stdin io.Reader
+
+	keys  chan []byte
+	wants chan struct{}
+	reads chan feedRead
+	stop  chan struct{}
+	once  sync.Once
+
+	// only accessed by the reading goroutine
+	pending []byte
+	reading bool
+	err     error
+}
+
+type feedRead struct {
+	data []byte
+	err  error
+}
+
+// newKeyFeed creates a keyFeed reading from the given stdin.
+func newKeyFeed(stdin io.Reader) *keyFeed {
+	f := &keyFeed{
+		stdin: stdin,
+		keys:  make(chan []byte, 16),
+		wants: make(chan struct{}, 1),
+		reads: make(chan feedRead),
+		stop:  make(chan struct{}),
+	}
+	go f.ioloop()
+	return f
+}
+
+// ioloop only reads from stdin when asked to, so that no keys are consumed once the feed is closed.
+func (f *keyFeed) ioloop() {
+	for {
+		select {
+		case <-f.wants:
+		case <-f.stop:
+			return
+		}
+
+		buf := make([]byte, 256)
+		n, err := f.stdin.Read(buf)
+
+		select {
+		case f.reads <- feedRead{data: buf[:n], err: err}:
+		case <-f.stop:
+			return
+		}
+
+		if err != nil {
+			return
+		}
+	}
+}
+
+// Push queues the given key to be read as if it had been typed by the user.
+func (f *keyFeed) Push(key rune) {
+	select {
+	case f.keys <- []byte(string(key)):
+	case <-f.stop:
+	}
+}
+
+// Read reads the next keys, either pushed ones or the ones typed on stdin, whichever comes first.
+func (f *keyFeed) Read(p []byte) (int, error) {
+	if len(f.pending) > 0 {
+		n := copy(p, f.pending)
+		f.pending = f.pending[n:]
+		return n, nil
+	}
+
+	if f.err != nil {
+		return 0, f.err
+	}
+
+	if !f.reading {
+		f.reading = true
+		f.wants <- struct{}{}
+	}
+
+	select {
+	case b := <-f.keys:
+		n := copy(p, b)
+		f.pending = b[n:]
+		return n, nil
+	case r := <-f.reads:
+		f.reading = false
+		n := copy(p, r.data)
+		f.pending = r.data[n:]
+		if len(f.pending) > 0 {
+			f.err = r.err
+			return n, nil
+		}
+		return n, r.err
+	case <-f.stop:
+		return 0, io.EOF
+	}
+}
+
+// Close stops the feed. The underlying stdin is left open.
+func (f *keyFeed) Close() error {
+	f.once.Do(func() {
+		close(f.stop)
+	})
+	return nil
+}
+
+// stopReader reads the stdin of a prompt, turning the keys it watches into a key which readline stops reading
+// after, such as ctrl-c or enter. This makes the prompt return as soon as one of the keys is pressed, before
+// readline reads any further, so that the keys typed next are left for whatever the key leads to, for example the
+// next question of a form.
+type stopReader struct {
+	stdin   io.Reader
+	keys    [][]byte
+	stop    byte
+	pressed bool
+
+	pending []byte
+	err     error
+}
+
+// watch sets the key to look for along with its alternates, and the key to turn them into, and resets whether
+// it was pressed. A nil key disables it.
+func (b *stopReader) watch(key *Key, stop rune) {
+	b.keys = nil
+	b.stop = byte(stop)
+	b.pressed = false
+
+	for k := key; k != nil; k = k.Alternate {
+		b.keys = append(b.keys, encodeKey(k.Code))
+	}
+}
+
+func (b *stopReader) Read(p []byte) (int, error) {
+	if len(b.pending) == 0 {
+		if b.err != nil {
+			return 0, b.err
+		}
+
+		buf := make([]byte, len(p))
+		n, err := b.stdin.Read(buf)
+		b.pending, b.err = buf[:n], err
+		if n == 0 {
+			return 0, err
+		}
+	}
+
+	end, size := len(b.pending), 0
+	for _, key := range b.keys {
+		if i := bytes.Index(b.pending, key); i >= 0 && i < end {
+			end, size = i, len(key)
+		}
+	}
+
+	if size > 0 && end == 0 {
+		b.pending = b.pending[size:]
+		b.pressed = true
+		p[0] = b.stop
+		return 1, nil
+	}
+
+	n := copy(p, b.pending[:end])
+	b.pending = b.pending[n:]
+	return n, nil
+}
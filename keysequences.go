@@ -0,0 +1,131 @@
+package promptui
+
+import (
+	"io"
+	"strings"
+
+	"github.com/chzyer/readline"
+)
+
+// KeySequences maps the byte sequences sent by a terminal for some of its keys to the key codes used inside
+// the prompts. It allows fixing the navigation on terminals sending unusual escape sequences for the arrows
+// or the other special keys. The common xterm sequences are always understood and don't need to be added.
+//
+// For example, this makes the "\x1b[[A" sequence sent by a terminal for its up arrow key move up in a select
+//
+//	promptui.KeySequences["\x1b[[A"] = promptui.KeyPrev
+//
+// The sequences are translated before reaching readline, for all the prompts and ReadKey. A sequence is only
+// recognized when the terminal sends it in a single write, as terminals do for a key press.
+var KeySequences = map[string]rune{}
+
+// metaSequences are the sequences readline decodes into its meta key codes, which have no single byte
+// representation.
+var metaSequences = map[rune]string{
+	readline.MetaBackward:  "\x1bb",
+	readline.MetaForward:   "\x1bf",
+	readline.MetaDelete:    "\x1bd",
+	readline.MetaBackspace: "\x1b\x7f",
+	readline.MetaTranspose: "\x1b\x14",
+}
+
+// moveSequences are the sequences sent by terminals for alt-up and alt-down, which readline reads as the plain
+// arrow keys. They are translated for the selects reordering their items with these keys.
+var moveSequences = map[string]rune{
+	"\x1b[1;3A":  KeyMoveUp,
+	"\x1b\x1b[A": KeyMoveUp,
+	"\x1b[1;3B":  KeyMoveDown,
+	"\x1b\x1b[B": KeyMoveDown,
+}
+
+// translateStdin wraps stdin so that the KeySequences are translated before being read, if there are any. A
+// nil stdin stands for the default one of readline.
+func translateStdin(stdin io.ReadCloser) io.ReadCloser {
+	return translateStdinWith(stdin, KeySequences)
+}
+
+// translateStdinWith wraps stdin like translateStdin, translating the given sequences instead of the
+// KeySequences.
+func translateStdinWith(stdin io.ReadCloser, sequences map[string]rune) io.ReadCloser {
+	if len(sequences) == 0 {
+		return stdin
+	}
+	if stdin == nil {
+		return readline.NewCancelableStdin(&sequenceReader{stdin: readline.Stdin, sequences: sequences})
+	}
+	return &sequenceReader{stdin: stdin, sequences: sequences}
+}
+
+// sequenceReader translates the KeySequences found in what is read from stdin into the bytes readline decodes
+// into the key codes they map to.
+type sequenceReader struct {
+	stdin     io.ReadCloser
+	sequences map[string]rune
+	pending   []byte
+	err       error
+}
+
+func (s *sequenceReader) Read(p []byte) (int, error) {
+	if len(s.pending) == 0 {
+		if s.err != nil {
+			return 0, s.err
+		}
+
+		buf := make([]byte, len(p))
+		n, err := s.stdin.Read(buf)
+		s.pending = translateSequences(buf[:n], s.sequences)
+		if len(s.pending) == 0 {
+			return 0, err
+		}
+		s.err = err
+	}
+
+	n := copy(p, s.pending)
+	s.pending = s.pending[n:]
+	return n, nil
+}
+
+func (s *sequenceReader) Close() error {
+	return s.stdin.Close()
+}
+
+// translateSequences replaces the sequences found in b, preferring the longest one when several match.
+func translateSequences(b []byte, sequences map[string]rune) []byte {
+	var out []byte
+
+	for i := 0; i < len(b); {
+		seq, code := matchSequence(string(b[i:]), sequences)
+		if seq == "" {
+			out = append(out, b[i])
+			i++
+			continue
+		}
+
+		out = append(out, encodeKey(code)...)
+		i += len(seq)
+	}
+
+	return out
+}
+
+// encodeKey returns the bytes readline decodes into the given key code.
+func encodeKey(code rune) []byte {
+	if meta, ok := metaSequences[code]; ok {
+		return []byte(meta)
+	}
+	return []byte(string(code))
+}
+
+// matchSequence returns the longest of the sequences s starts with, and its key code.
+func matchSequence(s string, sequences map[string]rune) (string, rune) {
+	var match string
+	var code rune
+
+	for seq, c := range sequences {
+		if len(seq) > len(match) && strings.HasPrefix(s, seq) {
+			match, code = seq, c
+		}
+	}
+
+	return match, code
+}
@@ -0,0 +1,78 @@
+package promptui
+
+import (
+	"strings"
+	"testing"
+)
+
+// withKeySequences registers the given sequences, returning a function restoring the previous ones.
+func withKeySequences(seqs map[string]rune) func() {
+	saved := KeySequences
+	KeySequences = seqs
+	return func() {
+		KeySequences = saved
+	}
+}
+
+func TestTranslateSequences(t *testing.T) {
+	defer withKeySequences(map[string]rune{
+		"\x1b[[A":  KeyPrev,
+		"\x1b[[AA": KeyNext,
+		"\x1b[[W":  KeyWordBackward,
+		"\x1b[[Z":  'z',
+	})()
+
+	tcs := []struct {
+		scenario string
+		input    string
+		expect   string
+	}{
+		{scenario: "no sequence", input: "abc", expect: "abc"},
+		{scenario: "known sequence", input: "\x1b[A", expect: "\x1b[A"},
+		{scenario: "custom sequence", input: "a\x1b[[Ab", expect: "a\x10b"},
+		{scenario: "longest sequence", input: "\x1b[[AA", expect: "\x0e"},
+		{scenario: "meta key", input: "\x1b[[W", expect: "\x1bb"},
+		{scenario: "printable key", input: "\x1b[[Z\x1b[[Z", expect: "zz"},
+	}
+
+	for _, tc := range tcs {
+		t.Run(tc.scenario, func(t *testing.T) {
+			got := string(translateSequences([]byte(tc.input), KeySequences))
+			if got != tc.expect {
+				t.Errorf("expected %q, got %q", tc.expect, got)
+			}
+		})
+	}
+}
+
+func TestKeySequences(t *testing.T) {
+	defer withKeySequences(map[string]rune{"\x1b[[B": KeyNext})()
+
+	t.Run("in a select", func(t *testing.T) {
+		s := Select{
+			Label: "Select Number",
+			Items: []string{"Zero", "One", "Two"},
+		}
+		scriptedSelect(&s, "\x1b[[B\x1b[[B\r")
+
+		idx, _, err := s.Run()
+		if err != nil {
+			t.Fatalf("Unexpected error %v", err)
+		}
+
+		if idx != 2 {
+			t.Errorf("Expected index 2, got %d", idx)
+		}
+	})
+
+	t.Run("when reading a key", func(t *testing.T) {
+		key, err := readKey(strings.NewReader("\x1b[[B"))
+		if err != nil {
+			t.Fatalf("Unexpected error %v", err)
+		}
+
+		if key != KeyNext {
+			t.Errorf("Expected %q, got %q", KeyNext, key)
+		}
+	})
+}
@@ -3,6 +3,7 @@ package list
 import (
 	"fmt"
 	"reflect"
+	"sort"
 	"strings"
 )
 
@@ -11,6 +12,24 @@ import (
 // the item fits the searched term.
 type Searcher func(input string, index int) bool
 
+// ScoredSearcher is a richer alternative to Searcher. It receives the searched term as runes along with the
+// item's index, and returns whether the item fits the term along with a Match describing how well it does.
+type ScoredSearcher func(query []rune, index int) (Match, bool)
+
+// Match describes how an item fits the term of a search done by a ScoredSearcher.
+type Match struct {
+	// Score ranks the items fitting the term, the higher the better.
+	Score int
+
+	// Ranges are the parts of the item's text matching the term, from the first to the last.
+	Ranges []Range
+}
+
+// Range is a part of a text, from the rune at Start up to the one at End, excluded.
+type Range struct {
+	Start, End int
+}
+
 // NotFound is an index returned when no item was selected. This could
 // happen due to a search without results.
 const NotFound = -1
@@ -25,6 +44,28 @@ type List struct {
 	size     int // size is the number of visible options
 	start    int
 	Searcher Searcher
+
+	// ScoredSearcher is used to search the items instead of the Searcher when set.
+	ScoredSearcher ScoredSearcher
+
+	// SortByScore sets whether the items found by the ScoredSearcher are listed from the best score to the worst,
+	// instead of in their original order. Items of equal score keep their original order. Each search then
+	// selects the best match, rather than keeping the selected item.
+	SortByScore bool
+
+	// matches are the matches of the items in scope, for a search done by the ScoredSearcher.
+	matches map[*interface{}]Match
+
+	// KeepSearchSpaces sets whether Search passes the term to the Searcher as is. By default, the whitespace
+	// around the term is trimmed, since it is rarely meant to be searched.
+	KeepSearchSpaces bool
+
+	// filter restricts the items in scope on top of the search, see SetFilter.
+	filter func(index int) bool
+
+	// the term of the current search, if searching
+	term      string
+	searching bool
 }
 
 // New creates and initializes a list of searchable items. The items attribute must be a slice type with a
@@ -64,25 +105,130 @@ func (l *List) Prev() {
 
 // Search allows the list to be filtered by a given term. The list must
 // implement the searcher function signature for this functionality to work.
+//
+// The selected item stays selected if it matches the term, otherwise the first
+// matching item becomes the selected one. When the matches are sorted by score,
+// the first one always becomes the selected one.
 func (l *List) Search(term string) {
-	term = strings.Trim(term, " ")
+	if !l.KeepSearchSpaces {
+		term = strings.TrimSpace(term)
+	}
+	selected := l.selected()
 	l.cursor = 0
 	l.start = 0
+	l.term = term
+	l.searching = true
 	l.search(term)
+	if !l.sorted() {
+		l.reselect(selected)
+	}
+}
+
+// SetFilter restricts the list to the items for which the filter returns true, given their index inside the
+// original items. The filter applies on top of the search, the current one and the following ones, until it is
+// replaced. A nil filter keeps all the items. The selected item stays selected if it passes the filter.
+func (l *List) SetFilter(filter func(index int) bool) {
+	selected := l.selected()
+	l.cursor = 0
+	l.start = 0
+	l.filter = filter
+	if l.searching {
+		l.search(l.term)
+	} else {
+		l.scope = l.unsearched()
+	}
+	if !l.searching || !l.sorted() {
+		l.reselect(selected)
+	}
+}
+
+// unsearched returns the items in scope when there is no search, the ones passing the filter.
+func (l *List) unsearched() []*interface{} {
+	if l.filter == nil {
+		return l.items
+	}
+
+	var scope []*interface{}
+	for i, item := range l.items {
+		if l.filter(i) {
+			scope = append(scope, item)
+		}
+	}
+	return scope
+}
+
+// sorted reports whether the searches sort the items by score.
+func (l *List) sorted() bool {
+	return l.SortByScore && l.ScoredSearcher != nil
 }
 
 // CancelSearch stops the current search and returns the list to its
-// original order.
+// original order, keeping the items passing the filter if any. The selected
+// item stays selected.
 func (l *List) CancelSearch() {
+	selected := l.selected()
 	l.cursor = 0
 	l.start = 0
-	l.scope = l.items
+	l.searching = false
+	l.scope = l.unsearched()
+	l.matches = nil
+	l.reselect(selected)
+}
+
+// selected returns the currently selected item, or nil if none is.
+func (l *List) selected() *interface{} {
+	if l.cursor < 0 || l.cursor >= len(l.scope) {
+		return nil
+	}
+	return l.scope[l.cursor]
+}
+
+// reselect moves the cursor back to the given item if it is still in scope.
+func (l *List) reselect(selected *interface{}) {
+	if selected == nil {
+		return
+	}
+
+	for i, item := range l.scope {
+		if item == selected {
+			l.SetCursor(i)
+			return
+		}
+	}
 }
 
 func (l *List) search(term string) {
 	var scope []*interface{}
+	l.matches = nil
+
+	if l.ScoredSearcher != nil {
+		query := []rune(term)
+		l.matches = make(map[*interface{}]Match)
+
+		for i, item := range l.items {
+			if l.filter != nil && !l.filter(i) {
+				continue
+			}
+			if match, ok := l.ScoredSearcher(query, i); ok {
+				scope = append(scope, item)
+				l.matches[item] = match
+			}
+		}
+
+		if l.SortByScore {
+			sort.SliceStable(scope, func(i, j int) bool {
+				return l.matches[scope[i]].Score > l.matches[scope[j]].Score
+			})
+		}
+
+		l.scope = scope
+		return
+	}
 
 	for i, item := range l.items {
+		if l.filter != nil && !l.filter(i) {
+			continue
+		}
 		if l.Searcher(term, i) {
 			scope = append(scope, item)
 		}
@@ -91,6 +237,55 @@ func (l *List) search(term string) {
 	l.scope = scope
 }
 
+// Len returns the number of items in the current scope of the list, which is the number of items
+// matching the current search or all the items when no search is active.
+func (l *List) Len() int {
+	return len(l.scope)
+}
+
+// Indexes returns the index inside the original items of every item in the current scope of the list, in
+// the order they are listed.
+func (l *List) Indexes() []int {
+	positions := make(map[*interface{}]int, len(l.items))
+	for i, item := range l.items {
+		positions[item] = i
+	}
+
+	indexes := make([]int, len(l.scope))
+	for i, item := range l.scope {
+		indexes[i] = positions[item]
+	}
+
+	return indexes
+}
+
+// Matches returns the match of every visible item, in the same order as Items. The matches are empty unless the
+// current search was done by the ScoredSearcher.
+func (l *List) Matches() []Match {
+	max := len(l.scope)
+	end := l.start + l.size
+
+	if end > max {
+		end = max
+	}
+
+	matches := make([]Match, 0, end-l.start)
+	for i := l.start; i < end; i++ {
+		matches = append(matches, l.matches[l.scope[i]])
+	}
+
+	return matches
+}
+
+// SetSize sets the number of visible items, keeping the selected item visible. Sizes lower than 1 are set to 1.
+func (l *List) SetSize(size int) {
+	if size < 1 {
+		size = 1
+	}
+	l.size = size
+	l.SetCursor(l.cursor)
+}
+
 // Start returns the current render start position of the list.
 func (l *List) Start() int {
 	return l.start
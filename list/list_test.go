@@ -3,6 +3,7 @@ package list
 import (
 	"fmt"
 	"reflect"
+	"strings"
 	"testing"
 )
 
@@ -155,6 +156,153 @@ func TestListComparion(t *testing.T) {
 	})
 }
 
+func TestListSearch(t *testing.T) {
+	words := []string{"apple", "banana", "avocado", "blueberry", "apricot", "cherry"}
+
+	l, err := New(words, 2)
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+
+	l.Searcher = func(input string, index int) bool {
+		return strings.Contains(words[index], input)
+	}
+
+	tcs := []struct {
+		scenario string
+		term     string
+		move     string
+		selected string
+	}{
+		{scenario: "narrow the search", term: "a", selected: "apple"},
+		{scenario: "move to another match", term: "a", move: "next", selected: "banana"},
+		{scenario: "narrow while the selected item matches", term: "an", selected: "banana"},
+		{scenario: "widen while the selected item matches", term: "a", selected: "banana"},
+		{scenario: "narrow past the selected item", term: "ap", selected: "apple"},
+		{scenario: "move to the last match", term: "ap", move: "next", selected: "apricot"},
+		{scenario: "widen with the selected item out of view", term: "a", selected: "apricot"},
+		{scenario: "cancel the search", selected: "apricot"},
+	}
+
+	for _, tc := range tcs {
+		t.Run(tc.scenario, func(t *testing.T) {
+			switch {
+			case tc.move == "next":
+				l.Next()
+			case tc.term == "":
+				l.CancelSearch()
+			default:
+				l.Search(tc.term)
+			}
+
+			list, idx := l.Items()
+			if idx == NotFound {
+				t.Fatalf("expected %q to be selected, got none", tc.selected)
+			}
+
+			if got := list[idx].(string); got != tc.selected {
+				t.Errorf("expected selected to be %q, got %q", tc.selected, got)
+			}
+
+			if got := words[l.Index()]; got != tc.selected {
+				t.Errorf("expected index of %q, got %q", tc.selected, got)
+			}
+		})
+	}
+}
+
+func TestListScoredSearch(t *testing.T) {
+	words := []string{"apple", "banana", "avocado", "blueberry"}
+
+	l, err := New(words, 2)
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+
+	l.Searcher = func(input string, index int) bool {
+		t.Fatalf("expected the Searcher not to be called, got %q", input)
+		return false
+	}
+	l.ScoredSearcher = func(query []rune, index int) (Match, bool) {
+		word := []rune(words[index])
+		for i := 0; i+len(query) <= len(word); i++ {
+			if string(word[i:i+len(query)]) == string(query) {
+				return Match{Score: len(word) - i, Ranges: []Range{{Start: i, End: i + len(query)}}}, true
+			}
+		}
+		return Match{}, false
+	}
+
+	l.Search("an")
+
+	got := fmt.Sprint(l.Indexes())
+	if got != "[1]" {
+		t.Errorf("expected [1], got %s", got)
+	}
+
+	exp := []Match{{Score: 5, Ranges: []Range{{Start: 1, End: 3}}}}
+	if matches := l.Matches(); !reflect.DeepEqual(matches, exp) {
+		t.Errorf("expected matches %v, got %v", exp, matches)
+	}
+
+	l.CancelSearch()
+
+	exp = []Match{{}, {}}
+	if matches := l.Matches(); !reflect.DeepEqual(matches, exp) {
+		t.Errorf("expected matches %v, got %v", exp, matches)
+	}
+}
+
+func TestListSortByScore(t *testing.T) {
+	words := []string{"banana", "apple", "grape", "pineapple", "papaya"}
+
+	l, err := New(words, 5)
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+
+	// the earlier the term is found, the better
+	l.ScoredSearcher = func(query []rune, index int) (Match, bool) {
+		i := strings.Index(words[index], string(query))
+		if i < 0 {
+			return Match{}, false
+		}
+		return Match{Score: 10 - i}, true
+	}
+	l.SortByScore = true
+
+	tcs := []struct {
+		term   string
+		sorted []string
+	}{
+		{term: "a", sorted: []string{"apple", "banana", "papaya", "grape", "pineapple"}},
+		{term: "ap", sorted: []string{"apple", "papaya", "grape", "pineapple"}},
+		{term: "pa", sorted: []string{"papaya"}},
+		{term: "e", sorted: []string{"pineapple", "apple", "grape"}},
+	}
+
+	for _, tc := range tcs {
+		t.Run(tc.term, func(t *testing.T) {
+			l.Next()
+			l.Search(tc.term)
+
+			items, idx := l.Items()
+			got := make([]string, len(items))
+			for i, item := range items {
+				got[i] = item.(string)
+			}
+
+			if !reflect.DeepEqual(got, tc.sorted) {
+				t.Errorf("expected %q, got %q", tc.sorted, got)
+			}
+
+			if idx != 0 {
+				t.Errorf("expected the best match to be selected, got %d", idx)
+			}
+		})
+	}
+}
+
 func castList(list []interface{}) []rune {
 	result := make([]rune, len(list))
 	for i, l := range list {
@@ -162,3 +310,111 @@ func castList(list []interface{}) []rune {
 	}
 	return result
 }
+
+func TestListIndexes(t *testing.T) {
+	words := []string{"apple", "banana", "avocado", "blueberry"}
+
+	l, err := New(words, 2)
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+
+	l.Searcher = func(input string, index int) bool {
+		return strings.HasPrefix(words[index], input)
+	}
+
+	got := fmt.Sprint(l.Indexes())
+	if got != "[0 1 2 3]" {
+		t.Errorf("expected [0 1 2 3], got %s", got)
+	}
+
+	l.Search("b")
+
+	got = fmt.Sprint(l.Indexes())
+	if got != "[1 3]" {
+		t.Errorf("expected [1 3], got %s", got)
+	}
+}
+
+func TestListSetSize(t *testing.T) {
+	letters := []rune{'a', 'b', 'c', 'd', 'e', 'f'}
+
+	l, err := New(letters, 4)
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+
+	l.SetCursor(3)
+	l.SetSize(2)
+
+	list, idx := l.Items()
+	if got := string(runes(list)); got != "cd" {
+		t.Errorf("expected the visible items to be %q, got %q", "cd", got)
+	}
+	if list[idx] != 'd' {
+		t.Errorf("expected selected to be %q, got %q", 'd', list[idx])
+	}
+
+	l.SetSize(0)
+
+	list, _ = l.Items()
+	if got := string(runes(list)); got != "d" {
+		t.Errorf("expected the visible items to be %q, got %q", "d", got)
+	}
+}
+
+// runes converts the items of a list of runes back into runes.
+func runes(items []interface{}) []rune {
+	r := make([]rune, len(items))
+	for i, item := range items {
+		r[i] = item.(rune)
+	}
+	return r
+}
+
+func TestListSetFilter(t *testing.T) {
+	words := []string{"apple", "banana", "avocado", "blueberry", "apricot"}
+	fruits := map[string]bool{"apple": true, "banana": true, "blueberry": true, "apricot": true}
+
+	l, err := New(words, 5)
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+
+	l.Searcher = func(input string, index int) bool {
+		return strings.HasPrefix(words[index], input)
+	}
+
+	l.SetCursor(1)
+	l.SetFilter(func(index int) bool {
+		return fruits[words[index]]
+	})
+
+	if got := fmt.Sprint(l.Indexes()); got != "[0 1 3 4]" {
+		t.Errorf("expected [0 1 3 4], got %s", got)
+	}
+	if l.Index() != 1 {
+		t.Errorf("expected the selected item to stay selected, got %d", l.Index())
+	}
+
+	l.Search("a")
+
+	if got := fmt.Sprint(l.Indexes()); got != "[0 4]" {
+		t.Errorf("expected the search to apply along with the filter, got %s", got)
+	}
+
+	l.SetFilter(nil)
+
+	if got := fmt.Sprint(l.Indexes()); got != "[0 2 4]" {
+		t.Errorf("expected the search to remain without the filter, got %s", got)
+	}
+
+	l.SetFilter(func(index int) bool {
+		return index != 0
+	})
+	l.CancelSearch()
+
+	if got := fmt.Sprint(l.Indexes()); got != "[1 2 3 4]" {
+		t.Errorf("expected the filter to remain without the search, got %s", got)
+	}
+}
@@ -0,0 +1,59 @@
+package promptui
+
+import (
+	"sync"
+
+	"github.com/chzyer/readline"
+)
+
+// lineGuard serializes the calls readline makes to the listener of a prompt from its own goroutine with the
+// goroutine running the prompt, which reads and renders the same state once readline returns.
+//
+// Readline returns an entered line before calling the listener with the key that entered it, and returns an
+// interrupt before calling the listener with the interrupt key. The goroutine running the prompt thus waits for
+// the listener to be done with enter before reading the input, and closes the guard on an error so that a late
+// call doesn't render over the last frame.
+type lineGuard struct {
+	mu      sync.Mutex
+	closed  bool
+	entered chan struct{}
+}
+
+// newLineGuard creates a lineGuard for a single run of a prompt.
+func newLineGuard() *lineGuard {
+	return &lineGuard{entered: make(chan struct{}, 1)}
+}
+
+// listen wraps the listener of a prompt so that its calls are serialized and ignored once the guard is closed.
+func (g *lineGuard) listen(fn func([]rune, int, rune) ([]rune, int, bool)) func([]rune, int, rune) ([]rune, int, bool) {
+	return func(line []rune, pos int, key rune) ([]rune, int, bool) {
+		g.mu.Lock()
+		defer g.mu.Unlock()
+
+		if g.closed {
+			return nil, 0, false
+		}
+
+		if key == readline.CharEnter || key == readline.CharCtrlJ {
+			// the line is gone already, readline has nothing left to refresh
+			defer func() { g.entered <- struct{}{} }()
+			fn(line, pos, key)
+			return nil, 0, false
+		}
+
+		return fn(line, pos, key)
+	}
+}
+
+// wait is called once readline returns with the given error. It waits for the listener to handle the key that
+// entered the line, or closes the guard if there is no line.
+func (g *lineGuard) wait(err error) {
+	if err == nil {
+		<-g.entered
+		return
+	}
+
+	g.mu.Lock()
+	g.closed = true
+	g.mu.Unlock()
+}
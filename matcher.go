@@ -0,0 +1,129 @@
+package promptui
+
+import (
+	"unicode"
+
+	"github.com/manifoldco/promptui/list"
+)
+
+// Matcher matches a query against a set of candidates at once, which is how most fuzzy matching libraries work.
+// It allows using such a library, for example github.com/sahilm/fuzzy, to search the items of a select through
+// NewMatcherSearcher.
+//
+// Match returns a Result for each candidate fitting the query, in any order. Candidates that don't fit the query
+// have no Result. Match is called once per search, with the candidates of the items searched.
+type Matcher interface {
+	Match(query string, candidates []string) []Result
+}
+
+// Result is a candidate fitting the query given to a Matcher.
+type Result struct {
+	// Index is the position of the candidate among the candidates given to Match.
+	Index int
+
+	// Score ranks the candidates fitting the query, the higher the better.
+	Score int
+
+	// Ranges are the optional parts of the candidate matching the query, which the select templates can
+	// emphasize with the highlight function.
+	Ranges []list.Range
+}
+
+// DefaultMatcher is the Matcher used by NewMatcherSearcher when none is given. A candidate fits the query when it
+// holds all the runes of the query in the same order, ignoring case as FoldString does for single runes. The
+// candidates matching the query in consecutive runes, at the start of their words, or in fewer parts score higher.
+var DefaultMatcher Matcher = fuzzyMatcher{}
+
+// NewMatcherSearcher creates a ScoredSearcher matching the query against the candidates with the Matcher, or
+// DefaultMatcher if it is nil. The candidates function returns the text searched for each item, in the same order
+// as the Items of the select, for example their names. It is called at the start of each search, so that the
+// candidates follow the items deleted, moved or replaced while the select runs.
+func NewMatcherSearcher(matcher Matcher, candidates func() []string) list.ScoredSearcher {
+	if matcher == nil {
+		matcher = DefaultMatcher
+	}
+
+	// the results of the ongoing search, since the searcher is called once for each item, in order
+	last := -1
+	var results map[int]Result
+
+	return func(q []rune, index int) (list.Match, bool) {
+		if index <= last {
+			results = nil
+		}
+		last = index
+
+		if results == nil {
+			results = make(map[int]Result)
+			for _, r := range matcher.Match(string(q), candidates()) {
+				results[r.Index] = r
+			}
+		}
+
+		r, ok := results[index]
+		return list.Match{Score: r.Score, Ranges: r.Ranges}, ok
+	}
+}
+
+type fuzzyMatcher struct{}
+
+// Match matches the runes of the query in order, as early as possible in each candidate.
+func (fuzzyMatcher) Match(query string, candidates []string) []Result {
+	q := []rune(query)
+	for i, r := range q {
+		q[i] = matchRune(r)
+	}
+
+	var results []Result
+	for i, candidate := range candidates {
+		if r, ok := fuzzyMatch(q, []rune(candidate)); ok {
+			r.Index = i
+			results = append(results, r)
+		}
+	}
+
+	return results
+}
+
+// fuzzyMatch matches the folded query against the runes of a candidate.
+func fuzzyMatch(query, candidate []rune) (Result, bool) {
+	var r Result
+	j := 0
+
+	for i, c := range candidate {
+		if j == len(query) {
+			break
+		}
+		if matchRune(c) != query[j] {
+			continue
+		}
+
+		if n := len(r.Ranges); n > 0 && r.Ranges[n-1].End == i {
+			r.Ranges[n-1].End++
+			r.Score += 3
+		} else {
+			if n > 0 {
+				r.Score--
+			}
+			r.Ranges = append(r.Ranges, list.Range{Start: i, End: i + 1})
+		}
+		if i == 0 || !unicode.IsLetter(candidate[i-1]) && !unicode.IsDigit(candidate[i-1]) {
+			r.Score += 2
+		}
+		j++
+	}
+
+	if j < len(query) {
+		return Result{}, false
+	}
+
+	return r, true
+}
+
+// matchRune folds r like FoldString, for the runes folding to a single rune.
+func matchRune(r rune) rune {
+	if r == 'İ' || r == 'ı' {
+		return 'i'
+	}
+	return foldRune(r)
+}
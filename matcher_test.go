@@ -0,0 +1,130 @@
+package promptui
+
+import (
+	"reflect"
+	"sort"
+	"testing"
+
+	"github.com/manifoldco/promptui/list"
+)
+
+func TestDefaultMatcher(t *testing.T) {
+	candidates := []string{"git status", "go test", "Get Started", "gist"}
+
+	tcs := []struct {
+		scenario string
+		query    string
+		expect   []string
+	}{
+		{scenario: "match the runes in order", query: "gst", expect: []string{"git status", "Get Started", "go test", "gist"}},
+		{scenario: "ignore case", query: "GS", expect: []string{"git status", "Get Started", "go test", "gist"}},
+		{scenario: "prefer consecutive runes", query: "est", expect: []string{"go test", "Get Started"}},
+		{scenario: "prefer the start of words", query: "gt", expect: []string{"go test", "git status", "Get Started", "gist"}},
+		{scenario: "skip the candidates missing a rune", query: "sta", expect: []string{"git status", "Get Started"}},
+		{scenario: "match nothing", query: "xyz"},
+	}
+
+	for _, tc := range tcs {
+		t.Run(tc.scenario, func(t *testing.T) {
+			results := DefaultMatcher.Match(tc.query, candidates)
+			sort.SliceStable(results, func(i, j int) bool {
+				return results[i].Score > results[j].Score
+			})
+
+			var got []string
+			for _, r := range results {
+				got = append(got, candidates[r.Index])
+			}
+
+			if !reflect.DeepEqual(got, tc.expect) {
+				t.Errorf("expected %q, got %q", tc.expect, got)
+			}
+		})
+	}
+
+	t.Run("return the matched ranges", func(t *testing.T) {
+		results := DefaultMatcher.Match("stus", []string{"git status"})
+		if len(results) != 1 {
+			t.Fatalf("expected a result, got %v", results)
+		}
+
+		exp := []list.Range{{Start: 4, End: 6}, {Start: 8, End: 10}}
+		if !reflect.DeepEqual(results[0].Ranges, exp) {
+			t.Errorf("expected ranges %v, got %v", exp, results[0].Ranges)
+		}
+	})
+}
+
+type countingMatcher struct {
+	calls int
+}
+
+func (m *countingMatcher) Match(query string, candidates []string) []Result {
+	m.calls++
+	return []Result{{Index: len(candidates) - 1, Score: len(query)}}
+}
+
+func TestMatcherSearcher(t *testing.T) {
+	candidates := []string{"foo", "bar", "baz"}
+
+	t.Run("calls the matcher once per search", func(t *testing.T) {
+		m := &countingMatcher{}
+		searcher := NewMatcherSearcher(m, func() []string { return candidates })
+
+		for _, query := range []string{"a", "a", "ab"} {
+			for i := range candidates {
+				match, ok := searcher([]rune(query), i)
+				if last := i == len(candidates)-1; ok != last {
+					t.Errorf("expected item %d to match %q: %t, got %t", i, query, last, ok)
+				} else if ok && match.Score != len(query) {
+					t.Errorf("expected a score of %d, got %d", len(query), match.Score)
+				}
+			}
+		}
+
+		if m.calls != 3 {
+			t.Errorf("expected 3 calls to the matcher, got %d", m.calls)
+		}
+	})
+
+	t.Run("uses the default matcher", func(t *testing.T) {
+		searcher := NewMatcherSearcher(nil, func() []string { return candidates })
+
+		s := Select{Label: "Item", Items: candidates, ScoredSearcher: searcher, SortByScore: true}
+		scriptedSelect(&s, "/bz\r")
+
+		idx, value, err := s.Run()
+		if err != nil {
+			t.Fatalf("Unexpected error %v", err)
+		}
+
+		if idx != 2 || value != "baz" {
+			t.Errorf("Expected (2, %q), got (%d, %q)", "baz", idx, value)
+		}
+	})
+
+	t.Run("follows the deleted items", func(t *testing.T) {
+		names := append([]string(nil), candidates...)
+		searcher := NewMatcherSearcher(nil, func() []string { return names })
+
+		s := Select{
+			Label:          "Item",
+			Items:          append([]string(nil), candidates...),
+			ScoredSearcher: searcher,
+			OnDelete: func(index int) error {
+				names = append(names[:index:index], names[index+1:]...)
+				return nil
+			},
+		}
+		scriptedSelect(&s, "\x04/bz\r")
+
+		idx, value, err := s.Run()
+		if err != nil {
+			t.Fatalf("Unexpected error %v", err)
+		}
+
+		if idx != 1 || value != "baz" {
+			t.Errorf("Expected (1, %q), got (%d, %q)", "baz", idx, value)
+		}
+	})
+}
@@ -0,0 +1,164 @@
+package promptui
+
+import (
+	"io"
+	"strconv"
+	"strings"
+	"sync"
+)
+
+const (
+	// mouseOn enables the reporting of mouse clicks and wheel events with the SGR encoding.
+	mouseOn = "\x1b[?1000h\x1b[?1006h"
+	// mouseOff disables the reporting enabled by mouseOn.
+	mouseOff = "\x1b[?1000l\x1b[?1006l"
+	// queryCursor asks the terminal to report the position of the cursor.
+	queryCursor = "\x1b[6n"
+)
+
+// keyMouseClick is the key code sent by a mouseReader for a click, which readline passes along to the select
+// as is.
+const keyMouseClick rune = 29
+
+// mouseReader translates the mouse events reported by the terminal into keys: the wheel into the previous and
+// next keys, and a left click into keyMouseClick, keeping the clicked row. It also keeps the cursor positions
+// reported by the terminal, which locate the select on the screen.
+type mouseReader struct {
+	stdin io.Reader
+	up    []byte
+	down  []byte
+
+	mu     sync.Mutex
+	row    int // last row reported for the cursor, 0 if unknown
+	clicks []int
+
+	pending []byte
+	err     error
+}
+
+// newMouseReader creates a mouseReader sending the given key codes for the wheel.
+func newMouseReader(stdin io.Reader, up, down rune) *mouseReader {
+	return &mouseReader{stdin: stdin, up: encodeKey(up), down: encodeKey(down)}
+}
+
+func (m *mouseReader) Read(p []byte) (int, error) {
+	if len(m.pending) == 0 {
+		if m.err != nil {
+			return 0, m.err
+		}
+
+		buf := make([]byte, len(p))
+		n, err := m.stdin.Read(buf)
+		m.pending = m.translate(buf[:n])
+		if len(m.pending) == 0 {
+			return 0, err
+		}
+		m.err = err
+	}
+
+	n := copy(p, m.pending)
+	m.pending = m.pending[n:]
+	return n, nil
+}
+
+// translate replaces the mouse events and cursor position reports found in b.
+func (m *mouseReader) translate(b []byte) []byte {
+	var out []byte
+
+	for i := 0; i < len(b); {
+		params, final, n := parseCSI(string(b[i:]))
+		if n == 0 {
+			out = append(out, b[i])
+			i++
+			continue
+		}
+		i += n
+
+		switch {
+		case final == 'R' && len(params) == 2:
+			m.mu.Lock()
+			m.row = params[0]
+			m.mu.Unlock()
+		case (final == 'M' || final == 'm') && len(params) == 3 && params[0] < 0:
+			// SGR mouse events are marked with a '<', reported as a negative button
+			switch button := -params[0] - 1; {
+			case final == 'M' && button == 64:
+				out = append(out, m.up...)
+			case final == 'M' && button == 65:
+				out = append(out, m.down...)
+			case final == 'M' && button == 0:
+				m.mu.Lock()
+				m.clicks = append(m.clicks, params[2])
+				m.mu.Unlock()
+				out = append(out, string(keyMouseClick)...)
+			}
+		default:
+			out = append(out, b[i-n:i]...)
+		}
+	}
+
+	return out
+}
+
+// click returns the row of the oldest click not handled yet and the last row reported for the cursor.
+func (m *mouseReader) click() (int, int, bool) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	if len(m.clicks) == 0 {
+		return 0, 0, false
+	}
+
+	row := m.clicks[0]
+	m.clicks = m.clicks[1:]
+
+	return row, m.row, m.row != 0
+}
+
+// parseCSI parses the control sequence s starts with, made of numeric parameters separated by semicolons and
+// ended by a final letter, like "\x1b[12;1R". A parameter list starting with '<' makes the first parameter
+// negative, minus one, so that "\x1b[<0;5;5M" gives -1. It returns the length of the sequence, zero if s
+// doesn't start with one.
+func parseCSI(s string) ([]int, byte, int) {
+	if !strings.HasPrefix(s, "\x1b[") {
+		return nil, 0, 0
+	}
+
+	body := s[2:]
+	private := strings.HasPrefix(body, "<")
+	if private {
+		body = body[1:]
+	}
+
+	end := strings.IndexFunc(body, func(c rune) bool {
+		return c != ';' && (c < '0' || c > '9')
+	})
+	if end <= 0 {
+		return nil, 0, 0
+	}
+
+	final := body[end]
+	if final < 'A' || final > 'z' {
+		return nil, 0, 0
+	}
+
+	var params []int
+	for _, p := range strings.Split(body[:end], ";") {
+		n, err := strconv.Atoi(p)
+		if err != nil {
+			return nil, 0, 0
+		}
+		params = append(params, n)
+	}
+
+	if private {
+		params[0] = -params[0] - 1
+	}
+
+	length := 2 + end + 1
+	if private {
+		length++
+	}
+
+	return params, final, length
+}
@@ -0,0 +1,95 @@
+package promptui
+
+import (
+	"fmt"
+	"strings"
+	"testing"
+)
+
+func TestParseCSI(t *testing.T) {
+	tcs := []struct {
+		input  string
+		params []int
+		final  byte
+		length int
+	}{
+		{input: "\x1b[12;1R", params: []int{12, 1}, final: 'R', length: 7},
+		{input: "\x1b[<0;5;10Mrest", params: []int{-1, 5, 10}, final: 'M', length: 10},
+		{input: "\x1b[<64;1;2m", params: []int{-65, 1, 2}, final: 'm', length: 10},
+		{input: "\x1b[1;5A", params: []int{1, 5}, final: 'A', length: 6},
+		{input: "\x1b[A"},
+		{input: "\x1b[3~"},
+		{input: "\x1b[<;M"},
+		{input: "abc"},
+	}
+
+	for _, tc := range tcs {
+		t.Run(fmt.Sprintf("%q", tc.input), func(t *testing.T) {
+			params, final, length := parseCSI(tc.input)
+
+			if fmt.Sprint(params) != fmt.Sprint(tc.params) || final != tc.final || length != tc.length {
+				t.Errorf("expected %v %q %d, got %v %q %d", tc.params, tc.final, tc.length, params, final, length)
+			}
+		})
+	}
+}
+
+func TestMouseReader(t *testing.T) {
+	m := newMouseReader(strings.NewReader(""), KeyPrev, KeyNext)
+
+	got := string(m.translate([]byte("a\x1b[<64;1;1M\x1b[<65;1;1Mb\x1b[5;1R\x1b[<0;3;7M\x1b[<0;3;7m\x1b[A")))
+	expect := "a" + string(KeyPrev) + string(KeyNext) + "b" + string(keyMouseClick) + "\x1b[A"
+	if got != expect {
+		t.Errorf("expected %q, got %q", expect, got)
+	}
+
+	row, bottom, ok := m.click()
+	if !ok || row != 7 || bottom != 5 {
+		t.Errorf("expected a click on row 7 with the cursor on row 5, got %d %d %v", row, bottom, ok)
+	}
+
+	if _, _, ok := m.click(); ok {
+		t.Errorf("expected no more clicks")
+	}
+}
+
+func TestSelectMouse(t *testing.T) {
+	wheelDown := "\x1b[<65;4;4M"
+
+	tcs := []struct {
+		scenario string
+		keys     string
+		expect   int
+	}{
+		{scenario: "wheel", keys: wheelDown + wheelDown + "\x1b[<64;4;4M\r", expect: 1},
+		// the select spans rows 15 to 19 when the cursor is reported below it on row 20
+		{scenario: "click an item", keys: "\x1b[20;1R\x1b[<0;4;19M\x1b[<0;4;19m", expect: 2},
+		{scenario: "click the label", keys: "\x1b[20;1R\x1b[<0;4;16M\r", expect: 0},
+		{scenario: "click before the cursor is reported", keys: "\x1b[<0;4;19M\r", expect: 0},
+	}
+
+	for _, tc := range tcs {
+		t.Run(tc.scenario, func(t *testing.T) {
+			s := Select{
+				Label:        "Select Number",
+				Items:        []string{"Zero", "One", "Two"},
+				MouseEnabled: true,
+			}
+			out := scriptedSelect(&s, tc.keys)
+
+			idx, _, err := s.Run()
+			if err != nil {
+				t.Fatalf("Unexpected error %v", err)
+			}
+
+			if idx != tc.expect {
+				t.Errorf("Expected index %d, got %d", tc.expect, idx)
+			}
+
+			output := out.String()
+			if !strings.HasPrefix(strings.TrimPrefix(output, hideCursor), mouseOn) || !strings.HasSuffix(output, mouseOff) {
+				t.Errorf("Expected mouse reporting to be enabled then disabled, got %q", output)
+			}
+		})
+	}
+}
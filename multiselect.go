@@ -0,0 +1,648 @@
+package promptui
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+	"sort"
+	"strings"
+	"text/template"
+
+	"github.com/chzyer/readline"
+	"github.com/juju/ansiterm"
+	"github.com/manifoldco/promptui/list"
+	"github.com/manifoldco/promptui/screenbuf"
+)
+
+// MultiSelect represents a list of items in which any number of them can be checked, for example to choose the
+// features to enable or the files to delete. The user moves around the list like in a Select, checks or unchecks
+// the active item with the Toggle key and confirms the checked items by pressing enter.
+type MultiSelect struct {
+	// Label is the text displayed on top of the list to direct input. The IconInitial value "?" will be
+	// appended automatically to the label so it does not need to be added.
+	Label interface{}
+
+	// Items are the items to display inside the list. It expect a slice of any kind of values, including
+	// strings. See the Select docs for how they are displayed.
+	Items interface{}
+
+	// DefaultSelected are the indexes of the items checked when the list opens. They can be unchecked like any
+	// other item.
+	DefaultSelected []int
+
+	// MinSelect is the minimum number of items to check. Pressing enter with fewer items checked displays an
+	// error and keeps the list open. Zero means no minimum.
+	MinSelect int
+
+	// MaxSelect is the maximum number of items that can be checked. Checking more items rings the terminal
+	// bell and displays an error instead. Zero means no maximum.
+	MaxSelect int
+
+	// Size is the number of items that should appear on the list before scrolling is necessary. Defaults to 5.
+	Size int
+
+	// CursorPos is the initial position of the cursor.
+	CursorPos int
+
+	// IsVimMode sets whether to use vim mode when using readline in the command prompt. Look at
+	// https://godoc.org/github.com/chzyer/readline#Config for more information on readline.
+	IsVimMode bool
+
+	// HideHelp sets whether to hide help information.
+	HideHelp bool
+
+	// HideSelected sets whether to hide the text displayed after the items are successfully selected.
+	HideSelected bool
+
+	// Templates can be used to customize the list output. If nil is passed, the
+	// default templates are used. See the MultiSelectTemplates docs for more info.
+	Templates *MultiSelectTemplates
+
+	// Keys is the set of keys used to control the list. See the MultiSelectKeys docs for more info.
+	Keys *MultiSelectKeys
+
+	// Searcher is a function that can be implemented to refine the base searching algorithm. See the Select
+	// docs for more info. The Toggle key keeps working in search mode, so it can't be part of the searched
+	// terms.
+	Searcher list.Searcher
+
+	// StartInSearchMode sets whether or not the list should start in search mode or selection mode.
+	// For search mode to work, the Search property must be implemented.
+	StartInSearchMode bool
+
+	// A function that determines how to render the cursor
+	Pointer Pointer
+
+	Stdin  io.ReadCloser
+	Stdout io.WriteCloser
+
+	list    *list.List
+	checked map[int]bool
+}
+
+// MultiSelectKeys defines the available keys used by a multi select to enable the user to move around the
+// list, check items and trigger search mode. See the Key struct docs for more information on keys. The Toggle,
+// All and Clear keys are disabled when left empty.
+type MultiSelectKeys struct {
+	// Next is the key used to move to the next element inside the list. Defaults to down arrow key.
+	Next Key
+
+	// Prev is the key used to move to the previous element inside the list. Defaults to up arrow key.
+	Prev Key
+
+	// PageUp is the key used to jump back to the first element inside the list. Defaults to left arrow key.
+	PageUp Key
+
+	// PageDown is the key used to jump forward to the last element inside the list. Defaults to right arrow key.
+	PageDown Key
+
+	// Search is the key used to trigger the search mode for the list. Default to the "/" key.
+	Search Key
+
+	// Toggle is the key used to check or uncheck the active item. Defaults to the space key.
+	Toggle Key
+
+	// All is the key used to check all the items matching the current search, or every item when not
+	// searching. Defaults to ctrl-a.
+	All Key
+
+	// Clear is the key used to uncheck all the items matching the current search, or every item when not
+	// searching. Defaults to ctrl-x.
+	Clear Key
+}
+
+// MultiSelectTemplates allow a multi select list to be customized following stdlib text/template syntax. See
+// the SelectTemplates docs for examples.
+//
+// Each item is displayed as its prefix, followed by its checkbox and its text rendered by the Active or
+// Inactive template.
+type MultiSelectTemplates struct {
+	// Label is a text/template for the main command line label. Defaults to printing the label as it with
+	// the IconInitial.
+	//
+	// On top of the FuncMap, the label template can use the total function for the number of items and the
+	// selected function for the number of checked items. For example, `{{ . }} ({{ selected }}/3 selected)`.
+	Label string
+
+	// Active is a text/template for the text of the item currently active within the list.
+	Active string
+
+	// Inactive is a text/template for the text of the items not currently active inside the list.
+	Inactive string
+
+	// Selected is a text/template for the checked items once they were confirmed. It receives their text
+	// joined with commas.
+	Selected string
+
+	// Details is a text/template for the item currently active to show additional information. It can have
+	// multiple lines.
+	Details string
+
+	// Help is a text/template for displaying instructions at the top. By default it shows keys for movement,
+	// checking items and search.
+	Help string
+
+	// Error is a text/template for displaying why the checked items can't be changed or confirmed, when
+	// MinSelect or MaxSelect are set. It is displayed below the list until the user presses a key.
+	Error string
+
+	// ActivePrefix is the marker displayed before the active item. Defaults to the IconSelect.
+	ActivePrefix string
+
+	// InactivePrefix is the marker displayed before the inactive items. Defaults to a space.
+	InactivePrefix string
+
+	// CheckedPrefix is the checkbox displayed for the checked items. Defaults to the IconChecked.
+	CheckedPrefix string
+
+	// UncheckedPrefix is the checkbox displayed for the unchecked items. Defaults to the IconUnchecked.
+	UncheckedPrefix string
+
+	// FuncMap is a map of helper functions that can be used inside of templates according to the text/template
+	// documentation.
+	//
+	// By default, FuncMap contains the color functions used to color the text in templates. If FuncMap
+	// is overridden, the colors functions must be added in the override from promptui.FuncMap to work.
+	FuncMap template.FuncMap
+
+	label    *template.Template
+	active   *template.Template
+	inactive *template.Template
+	selected *template.Template
+	details  *template.Template
+	help     *template.Template
+	err      *template.Template
+}
+
+// Run executes the multi select list. It displays the label and the list of items, letting the user check any
+// of them. Run will keep the prompt alive until it has been canceled from the command prompt or the user
+// confirmed the checked items. It returns the indexes of the checked items inside Items, in ascending order, and
+// an error if any occurred during the execution.
+func (m *MultiSelect) Run() ([]int, error) {
+	if m.Size == 0 {
+		m.Size = 5
+	}
+
+	l, err := list.New(m.Items, m.Size)
+	if err != nil {
+		return nil, err
+	}
+	l.Searcher = m.Searcher
+
+	m.list = l
+
+	if m.MaxSelect > 0 && m.MinSelect > m.MaxSelect {
+		return nil, fmt.Errorf("multi select min %d must not be greater than max %d", m.MinSelect, m.MaxSelect)
+	}
+
+	checked := make(map[int]bool)
+	m.checked = checked
+	for _, i := range m.DefaultSelected {
+		if i < 0 || i >= l.Len() {
+			return nil, fmt.Errorf("default selected index %d is out of range for %d items", i, l.Len())
+		}
+		checked[i] = true
+	}
+
+	m.setKeys()
+
+	err = m.prepareTemplates()
+	if err != nil {
+		return nil, err
+	}
+
+	stdin := readline.Stdin
+	if m.Stdin != nil {
+		stdin = m.Stdin
+	}
+	stdin = translateStdin(stdin)
+
+	c := &readline.Config{
+		Stdin:          readline.NewCancelableStdin(stdin),
+		Stdout:         m.Stdout,
+		VimMode:        m.IsVimMode,
+		HistoryLimit:   -1,
+		UniqueEditLine: true,
+	}
+
+	err = c.Init()
+	if err != nil {
+		return nil, err
+	}
+
+	saveTerminal()
+	defer pushTerminalState(c.Stdout, false)()
+
+	rl, err := readline.NewEx(c)
+	if err != nil {
+		return nil, err
+	}
+	defer closeOnPanic(rl)
+
+	rl.Write([]byte(hideCursor))
+	sb := screenbuf.New(rl)
+
+	cur := NewCursor("", m.Pointer, false)
+
+	canSearch := m.Searcher != nil
+	searchMode := m.StartInSearchMode
+
+	var selectErr error
+
+	m.list.SetCursor(m.CursorPos)
+
+	guard := newLineGuard()
+	c.SetListener(guard.listen(func(line []rune, pos int, key rune) ([]rune, int, bool) {
+		defer restoreOnPanic(rl)
+
+		// errors only stay displayed until the user presses a key
+		if key != 0 {
+			selectErr = nil
+		}
+
+		switch {
+		case key == KeyEnter:
+			return nil, 0, true
+		case key != 0 && m.Keys.Toggle.Matches(key):
+			if _, idx := m.list.Items(); idx != list.NotFound {
+				i := m.list.Index()
+				if !checked[i] && m.MaxSelect > 0 && m.count() >= m.MaxSelect {
+					rl.Terminal.Bell()
+					selectErr = fmt.Errorf("at most %d items can be selected", m.MaxSelect)
+					break
+				}
+				checked[i] = !checked[i]
+			}
+		case key != 0 && m.Keys.All.Matches(key):
+			indexes := m.list.Indexes()
+
+			n := m.count()
+			for _, i := range indexes {
+				if !checked[i] {
+					n++
+				}
+			}
+
+			if m.MaxSelect > 0 && n > m.MaxSelect {
+				rl.Terminal.Bell()
+				selectErr = fmt.Errorf("at most %d items can be selected", m.MaxSelect)
+				break
+			}
+
+			for _, i := range indexes {
+				checked[i] = true
+			}
+		case key != 0 && m.Keys.Clear.Matches(key):
+			for _, i := range m.list.Indexes() {
+				checked[i] = false
+			}
+		// the vi keys aren't alternates of the default keys since they are typed in the search
+		case m.Keys.Next.Matches(key) || (key == 'j' && !searchMode):
+			m.list.Next()
+		case m.Keys.Prev.Matches(key) || (key == 'k' && !searchMode):
+			m.list.Prev()
+		case m.Keys.Search.Matches(key):
+			if !canSearch {
+				break
+			}
+
+			if searchMode {
+				searchMode = false
+				cur.Replace("")
+				m.list.CancelSearch()
+			} else {
+				searchMode = true
+			}
+		case key == KeyBackspace || key == KeyCtrlH:
+			if !canSearch || !searchMode {
+				break
+			}
+
+			cur.Backspace()
+			if len(cur.Get()) > 0 {
+				m.list.Search(cur.Get())
+			} else {
+				m.list.CancelSearch()
+			}
+		case m.Keys.PageUp.Matches(key) || (key == 'h' && !searchMode):
+			m.list.PageUp()
+		case m.Keys.PageDown.Matches(key) || (key == 'l' && !searchMode):
+			m.list.PageDown()
+		default:
+			if canSearch && searchMode {
+				cur.Update(string(line))
+				m.list.Search(cur.Get())
+			}
+		}
+
+		if searchMode {
+			header := SearchPrompt + cur.Format()
+			sb.WriteString(header)
+		} else if !m.HideHelp {
+			sb.Write(m.renderHelp(canSearch))
+		}
+
+		label := render(m.Templates.label, m.Label)
+		sb.Write(label)
+
+		items, idx := m.list.Items()
+		indexes := m.list.Indexes()[m.list.Start():]
+		last := len(items) - 1
+
+		for i, item := range items {
+			page := " "
+
+			switch i {
+			case 0:
+				if m.list.CanPageUp() {
+					page = "↑"
+				}
+			case last:
+				if m.list.CanPageDown() {
+					page = "↓"
+				}
+			}
+
+			box := m.Templates.UncheckedPrefix
+			if checked[indexes[i]] {
+				box = m.Templates.CheckedPrefix
+			}
+
+			var output []byte
+			if i == idx {
+				output = []byte(page + " " + m.Templates.ActivePrefix + " " + box + " ")
+				output = append(output, render(m.Templates.active, item)...)
+			} else {
+				output = []byte(page + " " + m.Templates.InactivePrefix + " " + box + " ")
+				output = append(output, render(m.Templates.inactive, item)...)
+			}
+
+			sb.Write(output)
+		}
+
+		if selectErr != nil {
+			for _, line := range bytes.Split(render(m.Templates.err, selectErr), []byte("\n")) {
+				sb.Write(line)
+			}
+		}
+
+		if idx == list.NotFound {
+			sb.WriteString("")
+			sb.WriteString("No results")
+		} else {
+			for _, d := range m.renderDetails(items[idx]) {
+				sb.Write(d)
+			}
+		}
+
+		sb.Flush()
+
+		return nil, 0, true
+	}))
+
+	for {
+		_, err = rl.Readline()
+		guard.wait(err)
+		if err != nil || m.count() >= m.MinSelect {
+			break
+		}
+
+		selectErr = fmt.Errorf("at least %d items must be selected", m.MinSelect)
+	}
+
+	if err != nil {
+		switch {
+		case err == readline.ErrInterrupt, err.Error() == "Interrupt":
+			err = ErrInterrupt
+		case err == io.EOF:
+			err = ErrEOF
+		}
+
+		sb.Reset()
+		sb.WriteString("")
+		sb.WriteSequence([]byte(showCursor))
+		sb.Flush()
+		rl.Close()
+		return nil, err
+	}
+
+	selected := make([]int, 0, len(checked))
+	for i, ok := range checked {
+		if ok {
+			selected = append(selected, i)
+		}
+	}
+	sort.Ints(selected)
+
+	sb.WriteSequence([]byte(showCursor))
+	if m.HideSelected {
+		clearScreen(sb)
+	} else {
+		values := make([]string, len(selected))
+		for i, idx := range selected {
+			_, values[i], _ = SelectChoose(Select{Items: m.Items}, idx)
+		}
+
+		sb.Reset()
+		sb.Write(render(m.Templates.selected, strings.Join(values, ", ")))
+		sb.Flush()
+	}
+
+	rl.Close()
+
+	return selected, nil
+}
+
+func (m *MultiSelect) setKeys() {
+	if m.Keys != nil {
+		return
+	}
+	m.Keys = &MultiSelectKeys{
+		Prev:     Key{Code: KeyPrev, Display: KeyPrevDisplay},
+		Next:     Key{Code: KeyNext, Display: KeyNextDisplay},
+		PageUp:   Key{Code: KeyBackward, Display: KeyBackwardDisplay},
+		PageDown: Key{Code: KeyForward, Display: KeyForwardDisplay},
+		Search:   Key{Code: '/', Display: "/"},
+		Toggle:   Key{Code: ' ', Display: "space"},
+		All:      Key{Code: KeyMultiSelectAll, Display: KeyMultiSelectAllDisplay},
+		Clear:    Key{Code: KeyMultiSelectClear, Display: KeyMultiSelectClearDisplay},
+	}
+}
+
+func (m *MultiSelect) prepareTemplates() error {
+	tpls := m.Templates
+	if tpls == nil {
+		tpls = &MultiSelectTemplates{}
+	}
+
+	if tpls.FuncMap == nil {
+		tpls.FuncMap = FuncMap
+	}
+
+	if tpls.Label == "" {
+		tpls.Label = fmt.Sprintf("%s {{.}}: ", IconInitial)
+	}
+
+	tpl, err := template.New("").Funcs(tpls.FuncMap).Funcs(m.labelFuncs()).Parse(tpls.Label)
+	if err != nil {
+		return err
+	}
+
+	tpls.label = tpl
+
+	if tpls.Active == "" {
+		tpls.Active = "{{ . | underline }}"
+	}
+
+	tpl, err = template.New("").Funcs(tpls.FuncMap).Parse(tpls.Active)
+	if err != nil {
+		return err
+	}
+
+	tpls.active = tpl
+
+	if tpls.Inactive == "" {
+		tpls.Inactive = "{{.}}"
+	}
+
+	tpl, err = template.New("").Funcs(tpls.FuncMap).Parse(tpls.Inactive)
+	if err != nil {
+		return err
+	}
+
+	tpls.inactive = tpl
+
+	if tpls.Selected == "" {
+		tpls.Selected = fmt.Sprintf(`{{ %q | green }} {{ . | faint }}`, IconGood)
+	}
+
+	tpl, err = template.New("").Funcs(tpls.FuncMap).Parse(tpls.Selected)
+	if err != nil {
+		return err
+	}
+
+	tpls.selected = tpl
+
+	if tpls.Details != "" {
+		tpl, err = template.New("").Funcs(tpls.FuncMap).Parse(tpls.Details)
+		if err != nil {
+			return err
+		}
+
+		tpls.details = tpl
+	}
+
+	if tpls.Help == "" {
+		tpls.Help = `{{ "Use the arrow keys to navigate:" | faint }} {{ .NextKey | faint }} ` +
+			`{{ .PrevKey | faint }} {{ .PageDownKey | faint }} {{ .PageUpKey | faint }} ` +
+			`{{ .ToggleKey | faint }} {{ "toggles" | faint }} {{ .AllKey | faint }} {{ "checks all" | faint }} ` +
+			`{{ .ClearKey | faint }} {{ "clears" | faint }}` +
+			`{{ if .Search }} {{ "and" | faint }} {{ .SearchKey | faint }} {{ "toggles search" | faint }}{{ end }}`
+	}
+
+	tpl, err = template.New("").Funcs(tpls.FuncMap).Parse(tpls.Help)
+	if err != nil {
+		return err
+	}
+
+	tpls.help = tpl
+
+	if tpls.Error == "" {
+		tpls.Error = `{{ ">>" | red }} {{ . | red }}`
+	}
+
+	tpl, err = template.New("").Funcs(tpls.FuncMap).Parse(tpls.Error)
+	if err != nil {
+		return err
+	}
+
+	tpls.err = tpl
+
+	if tpls.ActivePrefix == "" {
+		tpls.ActivePrefix = IconSelect
+	}
+
+	if tpls.InactivePrefix == "" {
+		tpls.InactivePrefix = " "
+	}
+
+	if tpls.CheckedPrefix == "" {
+		tpls.CheckedPrefix = IconChecked
+	}
+
+	if tpls.UncheckedPrefix == "" {
+		tpls.UncheckedPrefix = IconUnchecked
+	}
+
+	m.Templates = tpls
+
+	return nil
+}
+
+// count returns the number of checked items.
+func (m *MultiSelect) count() int {
+	n := 0
+	for _, ok := range m.checked {
+		if ok {
+			n++
+		}
+	}
+	return n
+}
+
+// labelFuncs returns the template functions giving access to the state of the list inside the label template.
+func (m *MultiSelect) labelFuncs() template.FuncMap {
+	return template.FuncMap{
+		"total": func() int {
+			if m.list == nil {
+				return 0
+			}
+			return len(m.list.Indexes())
+		},
+		"selected": m.count,
+	}
+}
+
+func (m *MultiSelect) renderDetails(item interface{}) [][]byte {
+	if m.Templates.details == nil {
+		return nil
+	}
+
+	var buf bytes.Buffer
+	w := ansiterm.NewTabWriter(&buf, 0, 0, 8, ' ', 0)
+
+	err := m.Templates.details.Execute(w, item)
+	if err != nil {
+		fmt.Fprintf(w, "%v", item)
+	}
+
+	w.Flush()
+
+	return bytes.Split(buf.Bytes(), []byte("\n"))
+}
+
+func (m *MultiSelect) renderHelp(b bool) []byte {
+	keys := struct {
+		NextKey     string
+		PrevKey     string
+		PageDownKey string
+		PageUpKey   string
+		ToggleKey   string
+		AllKey      string
+		ClearKey    string
+		Search      bool
+		SearchKey   string
+	}{
+		NextKey:     m.Keys.Next.HelpText(),
+		PrevKey:     m.Keys.Prev.HelpText(),
+		PageDownKey: m.Keys.PageDown.HelpText(),
+		PageUpKey:   m.Keys.PageUp.HelpText(),
+		ToggleKey:   m.Keys.Toggle.HelpText(),
+		AllKey:      m.Keys.All.HelpText(),
+		ClearKey:    m.Keys.Clear.HelpText(),
+		Search:      b,
+		SearchKey:   m.Keys.Search.HelpText(),
+	}
+
+	return render(m.Templates.help, keys)
+}
@@ -0,0 +1,239 @@
+package promptui
+
+import (
+	"fmt"
+	"strings"
+	"testing"
+)
+
+func TestMultiSelect(t *testing.T) {
+	down := "\x1b[B"
+	items := []string{"lint", "test", "build", "deploy"}
+
+	tcs := []struct {
+		scenario string
+		defaults []int
+		keys     string
+		expect   []int
+	}{
+		{scenario: "nothing checked", keys: "\r", expect: []int{}},
+		{scenario: "check items", keys: " " + down + down + " \r", expect: []int{0, 2}},
+		{scenario: "uncheck an item", keys: " " + down + " " + " \r", expect: []int{0}},
+		{scenario: "defaults left untouched", defaults: []int{3, 1}, keys: "\r", expect: []int{1, 3}},
+		{scenario: "defaults toggled", defaults: []int{1, 3}, keys: down + " " + down + " \r", expect: []int{2, 3}},
+	}
+
+	for _, tc := range tcs {
+		t.Run(tc.scenario, func(t *testing.T) {
+			m := MultiSelect{
+				Label:           "Steps",
+				Items:           items,
+				DefaultSelected: tc.defaults,
+				Stdin:           scriptedStdin(tc.keys),
+				Stdout:          &nopWriteCloser{},
+			}
+
+			selected, err := m.Run()
+			if err != nil {
+				t.Fatalf("Unexpected error %v", err)
+			}
+
+			if fmt.Sprint(selected) != fmt.Sprint(tc.expect) {
+				t.Errorf("Expected %v, got %v", tc.expect, selected)
+			}
+		})
+	}
+
+	t.Run("when a default is out of range", func(t *testing.T) {
+		m := MultiSelect{Items: items, DefaultSelected: []int{4}}
+
+		_, err := m.Run()
+		if err == nil {
+			t.Errorf("Expected error, got none")
+		}
+	})
+
+	t.Run("when searching", func(t *testing.T) {
+		m := MultiSelect{
+			Label: "Steps",
+			Items: items,
+			Searcher: func(input string, index int) bool {
+				return strings.Contains(items[index], input)
+			},
+			Stdin:  scriptedStdin("/de \x7f\x7fb \r"),
+			Stdout: &nopWriteCloser{},
+		}
+
+		selected, err := m.Run()
+		if err != nil {
+			t.Fatalf("Unexpected error %v", err)
+		}
+
+		if fmt.Sprint(selected) != "[2 3]" {
+			t.Errorf("Expected [2 3], got %v", selected)
+		}
+	})
+
+	t.Run("renders the checked items", func(t *testing.T) {
+		out := &nopWriteCloser{}
+		m := MultiSelect{
+			Label:           "Steps",
+			Items:           items,
+			DefaultSelected: []int{0, 2},
+			Templates: &MultiSelectTemplates{
+				ActivePrefix:    ">",
+				CheckedPrefix:   "[x]",
+				UncheckedPrefix: "[ ]",
+				Active:          "{{ . }}",
+				Selected:        "{{ . }}",
+			},
+			Stdin:  scriptedStdin("\r"),
+			Stdout: out,
+		}
+
+		_, err := m.Run()
+		if err != nil {
+			t.Fatalf("Unexpected error %v", err)
+		}
+
+		for _, exp := range []string{"> [x] lint", "  [ ] test", "  [x] build", "lint, build"} {
+			if !strings.Contains(out.String(), exp) {
+				t.Errorf("Expected output to contain %q, got %q", exp, out.String())
+			}
+		}
+	})
+}
+
+func TestMultiSelectLimits(t *testing.T) {
+	down := "\x1b[B"
+	items := []string{"lint", "test", "build", "deploy"}
+
+	t.Run("when checking more than the maximum", func(t *testing.T) {
+		out := &nopWriteCloser{}
+		m := MultiSelect{
+			Label:     "Steps",
+			Items:     items,
+			MaxSelect: 2,
+			Templates: &MultiSelectTemplates{
+				Label: "{{ . }} ({{ selected }}/2 of {{ total }})",
+			},
+			Stdin:  scriptedStdin(" " + down + " " + down + " \r"),
+			Stdout: out,
+		}
+
+		selected, err := m.Run()
+		if err != nil {
+			t.Fatalf("Unexpected error %v", err)
+		}
+
+		if fmt.Sprint(selected) != "[0 1]" {
+			t.Errorf("Expected [0 1], got %v", selected)
+		}
+
+		for _, exp := range []string{"\a", "at most 2 items can be selected", "Steps (0/2 of 4)", "Steps (2/2 of 4)"} {
+			if !strings.Contains(out.String(), exp) {
+				t.Errorf("Expected output to contain %q, got %q", exp, out.String())
+			}
+		}
+	})
+
+	t.Run("when confirming less than the minimum", func(t *testing.T) {
+		out := &nopWriteCloser{}
+		m := MultiSelect{
+			Label:     "Steps",
+			Items:     items,
+			MinSelect: 1,
+			Stdin:     scriptedStdin("\r \r"),
+			Stdout:    out,
+		}
+
+		selected, err := m.Run()
+		if err != nil {
+			t.Fatalf("Unexpected error %v", err)
+		}
+
+		if fmt.Sprint(selected) != "[0]" {
+			t.Errorf("Expected [0], got %v", selected)
+		}
+
+		if !strings.Contains(out.String(), "at least 1 items must be selected") {
+			t.Errorf("Expected output to contain the error, got %q", out.String())
+		}
+	})
+
+	t.Run("when min is greater than max", func(t *testing.T) {
+		m := MultiSelect{Items: items, MinSelect: 3, MaxSelect: 2}
+
+		_, err := m.Run()
+		if err == nil {
+			t.Errorf("Expected error, got none")
+		}
+	})
+}
+
+func TestMultiSelectAll(t *testing.T) {
+	items := []string{"lint", "test", "build", "deploy"}
+	searcher := func(input string, index int) bool {
+		return strings.Contains(items[index], input)
+	}
+
+	tcs := []struct {
+		scenario string
+		defaults []int
+		max      int
+		keys     string
+		expect   []int
+	}{
+		{scenario: "check all", keys: "\x01\r", expect: []int{0, 1, 2, 3}},
+		{scenario: "clear all", defaults: []int{0, 3}, keys: "\x18\r", expect: []int{}},
+		{scenario: "check all matching the search", keys: "/t\x01\r", expect: []int{0, 1}},
+		{scenario: "clear all matching the search", defaults: []int{0, 1, 2}, keys: "/t\x18\r", expect: []int{2}},
+		{scenario: "check all past the maximum", defaults: []int{2}, max: 2, keys: "/t\x01\r", expect: []int{2}},
+	}
+
+	for _, tc := range tcs {
+		t.Run(tc.scenario, func(t *testing.T) {
+			m := MultiSelect{
+				Label:           "Steps",
+				Items:           items,
+				DefaultSelected: tc.defaults,
+				MaxSelect:       tc.max,
+				Searcher:        searcher,
+				Stdin:           scriptedStdin(tc.keys),
+				Stdout:          &nopWriteCloser{},
+			}
+
+			selected, err := m.Run()
+			if err != nil {
+				t.Fatalf("Unexpected error %v", err)
+			}
+
+			if fmt.Sprint(selected) != fmt.Sprint(tc.expect) {
+				t.Errorf("Expected %v, got %v", tc.expect, selected)
+			}
+		})
+	}
+
+	t.Run("without the keys", func(t *testing.T) {
+		m := MultiSelect{
+			Label: "Steps",
+			Items: items,
+			Keys: &MultiSelectKeys{
+				Prev:   Key{Code: KeyPrev, Display: KeyPrevDisplay},
+				Next:   Key{Code: KeyNext, Display: KeyNextDisplay},
+				Toggle: Key{Code: 'x', Display: "x"},
+			},
+			Stdin:  scriptedStdin("jx\r"),
+			Stdout: &nopWriteCloser{},
+		}
+
+		selected, err := m.Run()
+		if err != nil {
+			t.Fatalf("Unexpected error %v", err)
+		}
+
+		if fmt.Sprint(selected) != fmt.Sprint([]int{1}) {
+			t.Errorf("Expected %v, got %v", []int{1}, selected)
+		}
+	})
+}
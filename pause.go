@@ -0,0 +1,38 @@
+package promptui
+
+// PauseLabel is the label displayed by Pause when none is given.
+var PauseLabel = "Press enter to continue"
+
+// Pause displays the label and waits for the user to press enter before returning. If the label is empty,
+// PauseLabel is displayed instead. It uses the same terminal handling as Prompt, and the keys typed
+// before enter are ignored. It returns ErrInterrupt if the user pressed ctrl-c and ErrEOF if the user pressed
+// ctrl-d.
+func Pause(label string) error {
+	p := pausePrompt(label)
+	_, err := p.Run()
+	return err
+}
+
+// pausePrompt creates the prompt used by Pause.
+func pausePrompt(label string) *Prompt {
+	if label == "" {
+		label = PauseLabel
+	}
+
+	tpl := "{{ . | faint }}"
+
+	return &Prompt{
+		Label: label,
+		Templates: &PromptTemplates{
+			Prompt:  tpl,
+			Valid:   tpl,
+			Invalid: tpl,
+			Success: tpl,
+		},
+		Mask:        ' ',
+		HideEntered: true,
+		Pointer: func(to []rune) []rune {
+			return []rune{}
+		},
+	}
+}
@@ -0,0 +1,39 @@
+package promptui
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestPause(t *testing.T) {
+	t.Run("when enter is pressed", func(t *testing.T) {
+		out := &nopWriteCloser{}
+		p := pausePrompt("")
+		p.Stdin = scriptedStdin("abc\r")
+		p.Stdout = out
+
+		_, err := p.Run()
+		if err != nil {
+			t.Fatalf("Unexpected error %v", err)
+		}
+
+		if !strings.Contains(out.String(), PauseLabel) {
+			t.Errorf("Expected output to contain %q, got %q", PauseLabel, out.String())
+		}
+
+		if strings.Contains(out.String(), "abc") {
+			t.Errorf("Expected typed keys to be hidden, got %q", out.String())
+		}
+	})
+
+	t.Run("when interrupted", func(t *testing.T) {
+		p := pausePrompt("Press enter to deploy")
+		p.Stdin = scriptedStdin("\x03")
+		p.Stdout = &nopWriteCloser{}
+
+		_, err := p.Run()
+		if err != ErrInterrupt {
+			t.Errorf("Expected error %v, got %v", ErrInterrupt, err)
+		}
+	})
+}
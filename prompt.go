@@ -1,16 +1,25 @@
 package promptui
 
 import (
+	"bytes"
+	"context"
+	"errors"
 	"fmt"
 	"io"
 	"strings"
 	"text/template"
+	"time"
+	"unicode"
+	"unicode/utf8"
 
 	"github.com/chzyer/readline"
 	"github.com/manifoldco/promptui/screenbuf"
 )
 
 // Prompt represents a single line text field input with options for validation and input masks.
+//
+// An input too long for the width of the terminal scrolls horizontally to keep the cursor in view, with ‹ and ›
+// marking the parts out of view. Masked input and input changed by DisplayTransform aren't scrolled.
 type Prompt struct {
 	// Label is the value displayed on the command line prompt.
 	//
@@ -18,40 +27,212 @@ type Prompt struct {
 	// inside the templates. For example, `{{ .Name }}` will display the name property of a struct.
 	Label interface{}
 
+	// Hint is an optional help line displayed below the input while the user types, for example to show the
+	// expected format with "e.g. us-east-1". It is removed once the value is entered. The Hint template styles
+	// it, faint by default.
+	Hint string
+
+	// Name identifies the prompt among the questions of an AnswerSource. A Form uses the name of the question
+	// when it is empty.
+	Name string
+
+	// Answers is an optional source of answers to enter without asking the user, for example to replay the
+	// answers of a previous run. If it has an answer for the Name of the prompt, the prompt displays it as if
+	// it had been entered and returns it right away. Otherwise, the user is asked as usual.
+	Answers AnswerSource
+
 	// Default is the initial value for the prompt. This value will be displayed next to the prompt's label
 	// and the user will be able to view or change it depending on the options.
 	Default string
 
-	// AllowEdit lets the user edit the default value. If false, any key press
-	// other than <Enter> automatically clears the default value.
+	// AllowEdit lets the user edit the default value. If false, the first
+	// character typed replaces the default value and a deletion clears it,
+	// unless the user moves the cursor first to edit it in place.
 	AllowEdit bool
 
 	// Validate is an optional function that fill be used against the entered value in the prompt to validate it.
 	Validate ValidateFunc
 
+	// ValidateContext is an alternative to Validate for validations that can be cancelled, such as the ones
+	// making network calls. It receives a context which is cancelled when the context given to RunContext is,
+	// or once the prompt returns. It is used instead of Validate when set.
+	ValidateContext func(ctx context.Context, input string) error
+
+	// OnSubmit is an optional function called with the valid input once the user presses enter, which can change
+	// it before it is entered, for example to normalize it. It returns the value to enter and whether to enter
+	// it. When it returns false, the value is put back in the input for the user to edit it further, for
+	// example to offer a correction as in "did you mean…". An error is displayed like a validation error,
+	// leaving the input as it was. Validate is the simpler option when the input only needs to be checked.
+	// OnSubmit isn't called for the values from Answers.
+	OnSubmit func(input string) (string, bool, error)
+
+	// OnUnknownKey is an optional function called with the control keys the prompt has no use for, which are
+	// ignored instead of being inserted, so that the program can react to them. It is called before the prompt
+	// is rendered again, and can for example set the Hint to tell the user about the keys to use.
+	OnUnknownKey func(key rune)
+
+	// AllowEmpty lets the user enter an empty value, which is then returned as is without going through Validate
+	// or MinLength. Without it, an empty value is refused when the user presses enter, unless a Default is set,
+	// in which case it goes through Validate like any other value, or the Timeout enters it. AllowEmpty doesn't
+	// apply to confirm prompts, where an empty value stands for the default answer.
+	AllowEmpty bool
+
+	// CommandPrefix, when set, makes the values starting with it commands rather than input, such as ":quit"
+	// with a prefix of ":" for the slash-commands of a REPL. Commands are entered without going through the
+	// validation of the input, and RunInput tells them apart from the input. It is ignored by confirm prompts.
+	CommandPrefix string
+
 	// Mask is an optional rune that sets which character to display instead of the entered characters. This
 	// allows hiding private information like passwords.
 	Mask rune
 
+	// MaskString is an optional string whose characters are displayed in turn instead of the entered characters,
+	// repeating it as needed. It takes precedence over Mask and allows masking with a pattern made of several
+	// characters, such as "•◦".
+	MaskString string
+
+	// RevealFirst and RevealLast are the numbers of entered characters left visible at the start and at the end
+	// of the input when it is masked with Mask or MaskString, for example to only display the last 4 digits of
+	// a card number as "****1234".
+	RevealFirst int
+	RevealLast  int
+
+	// MaskWidth, when set along with Mask or MaskString, always displays that many mask characters followed by
+	// the cursor, whatever the number of entered characters, so that the length of a password can't be
+	// inferred from the screen. RevealFirst and RevealLast are then ignored.
+	MaskWidth int
+
+	// Secret sets whether to hide the entered characters entirely, without displaying a mask or the cursor. The
+	// entered text is also wiped from memory once the prompt returns. Since the text is returned as a string and
+	// goes through other buffers, which can't be wiped, this is only a best-effort to limit the number of copies
+	// of a secret left in memory.
+	Secret bool
+
 	// HideEntered sets whether to hide the text after the user has pressed enter.
 	HideEntered bool
 
+	// Recorder is an optional recorder of the keys read and the frames displayed by the prompt. The characters
+	// typed in a prompt with Secret or a Mask are redacted. See the Recorder docs for more info.
+	Recorder *Recorder
+
+	// DebugWriter is an optional writer receiving a copy of each frame displayed by the prompt, escape codes
+	// included, for example to snapshot its rendering or to make a recording of it. Each write holds one whole
+	// frame: the codes going back over the previous frame, the lines of the new one and the sequences written
+	// along with them. Unlike a Recorder, it receives neither the keys nor the codes written outside the
+	// frames, such as the ones hiding the cursor. Its errors are ignored.
+	DebugWriter io.Writer
+
+	// RedrawMode sets how the prompt is redrawn after each key. screenbuf.Incremental only rewrites the lines
+	// which changed, which reduces the flicker on slow terminals such as the ones reached over ssh. Defaults
+	// to screenbuf.Full, clearing all the lines before redrawing them.
+	RedrawMode screenbuf.RedrawMode
+
+	// Timeout is the time after which the prompt enters its current value on its own, as if the user had
+	// pressed enter, for example to accept the default value of an unattended script. It restarts each time
+	// the user presses a key. The default templates display the time left, which custom templates can display
+	// with the remaining function. Zero means no timeout.
+	Timeout time.Duration
+
+	// IdleTimeout is the time after which Run gives up and returns ErrTimeout when the user presses no key,
+	// for example for programs running unattended that would rather fail than wait forever. Unlike Timeout,
+	// nothing is entered, not even the default value. It restarts each time the user presses a key. Zero
+	// means no idle timeout.
+	IdleTimeout time.Duration
+
+	// CopyToClipboard sets whether to copy the entered value to the system clipboard once it is entered
+	// successfully. If it can't be copied, Run still returns the value, along with a ClipboardError.
+	CopyToClipboard bool
+
+	// MinLength is the minimum number of characters of the input. An input shorter than MinLength is invalid
+	// and can't be entered. Zero means no minimum.
+	MinLength int
+
+	// MaxLength is the maximum number of characters of the input. The keys typed once the input reached
+	// MaxLength are ignored, ringing the terminal bell instead. Zero means no maximum.
+	MaxLength int
+
+	// AllowRune restricts the characters of the input to the runes for which it returns true, for example
+	// unicode.IsDigit for a number. The keys typed for other runes are ignored, ringing the terminal bell
+	// instead, and they are left out of the pasted text. Nil allows any rune.
+	AllowRune func(r rune) bool
+
+	// CaseTransform forces the case of the typed and pasted characters as they are inserted, so the user sees
+	// the input in that case while typing, for example CaseUpper for country codes. AllowRune is given the
+	// characters in that case. The default value isn't transformed. CaseNone keeps the characters as typed.
+	CaseTransform CaseTransform
+
+	// MaxRetries is the number of times the user can enter an invalid value before Run gives up and returns
+	// ErrMaxRetries, which allows scripts to tell invalid input apart from an interrupt or EOF. The default
+	// ValidationError template displays the number of attempts left. Zero means the prompt keeps asking until
+	// a valid value is entered.
+	MaxRetries int
+
+	// AutoSubmitAt submits the input as if enter was pressed as soon as the user types that many characters,
+	// which suits codes of a fixed length such as one-time passwords. The input still goes through Validate,
+	// and the prompt stays open when it is invalid, until the input is edited back to that length. Zero
+	// disables it.
+	AutoSubmitAt int
+
+	// TabBehavior sets what pressing tab does, see the TabBehavior docs. Defaults to TabComplete, which rings
+	// the terminal bell when there is no Completer. A tab character can still be inserted with KeyQuote.
+	TabBehavior TabBehavior
+
+	// Completer is an optional function returning the candidate values for the input, for TabComplete. Tab
+	// completes the whole input, wherever the cursor is, up to the longest common prefix of the candidates
+	// starting with it, which is the candidate itself when there is only one.
+	Completer func(input string) []string
+
+	// History holds the values previously entered, which the user can search by pressing KeyHistorySearch. The
+	// search narrows down to the most recent entry holding the query as it is typed, pressing KeyHistorySearch
+	// again moves to the next older match, enter accepts the match and KeyHistoryCancel restores the input as it
+	// was. The values entered successfully are added to the History. It isn't used by confirm prompts nor by
+	// the ones hiding their input with Secret or a Mask.
+	History *History
+
 	// Templates can be used to customize the prompt output. If nil is passed, the
 	// default templates are used. See the PromptTemplates docs for more info.
 	Templates *PromptTemplates
 
+	// TemplateData holds values of the application that the templates can display next to the Label, such as
+	// the current user or environment. The templates read them with the data function, for example
+	// 	{{ data "User" }}
+	// Being a function, it never clashes with the fields of the Label, which templates still reach through the
+	// dot, and it takes precedence over a function of the same name in the FuncMap.
+	TemplateData map[string]interface{}
+
 	// IsConfirm makes the prompt ask for a yes or no ([Y/N]) question rather than request an input. When set,
 	// most properties related to input will be ignored.
 	IsConfirm bool
 
+	// ConfirmDefault is the answer of a confirm prompt when the user presses enter alone: yes when set, no
+	// otherwise. The default answer is capitalized in the [Y/n] or [y/N] hint. Setting the Default to "y" has the
+	// same effect.
+	ConfirmDefault bool
+
 	// IsVimMode enables vi-like movements (hjkl) and editing.
 	IsVimMode bool
 
+	// DisplayTransform is an optional function changing how the input is displayed, for example to group the
+	// digits of a card number. It only applies to the displayed text: the cursor works on the input as typed,
+	// and the validation and returned value use it as is. The cursor is displayed after the transformed text
+	// preceding it, which keeps it in place for transforms that insert characters between the typed ones.
+	DisplayTransform func(input string) string
+
 	// the Pointer defines how to render the cursor.
 	Pointer Pointer
 
+	// WordBoundary defines what a word is when moving the cursor or deleting text word by word. Defaults to
+	// SpaceWordBoundary, delimiting words with whitespace.
+	WordBoundary WordBoundaryFunc
+
 	Stdin  io.ReadCloser
 	Stdout io.WriteCloser
+
+	// columns returns the width of the terminal, which defaults to the one readline gets from the standard output.
+	columns func() int
+
+	// accepted is set when the value returned by the last run is the default value, accepted as is.
+	accepted bool
 }
 
 // PromptTemplates allow a prompt to be customized following stdlib
@@ -73,6 +254,24 @@ type Prompt struct {
 // 	'{{ . | red | cyan }}'
 //
 // See the doc of text/template for more info: https://golang.org/pkg/text/template/
+//
+// On top of the FuncMap, the templates can use the length function for the number of characters currently
+// entered. For example, this displays a counter for an input of at most 20 characters
+// 	'{{ . }} ({{ length }}/20)'
+//
+// They can also use the remaining function for the time left before the prompt times out, as a time.Duration
+// rounded to the second. For example
+// 	'{{ . }} (accepted in {{ remaining }}) '
+//
+// When the prompt is asked by a Form, the answers function returns the answers to the previous questions, and
+// the question and questions functions return the position of the question in the form, starting at 1, and the
+// number of questions. For example
+// 	'{{ . }} (question {{ question }} of {{ questions }}): '
+//
+// The attempts function returns the number of invalid values entered so far, and the attemptsLeft function the
+// number of values the user can still enter before the prompt returns ErrMaxRetries, or zero without MaxRetries.
+// For example, this ValidationError template warns about a lockout
+// 	'{{ . }}{{ with attemptsLeft }} ({{ . }} before lockout){{ end }}'
 type PromptTemplates struct {
 	// Prompt is a text/template for the prompt label displayed on the left side of the prompt.
 	Prompt string
@@ -91,10 +290,43 @@ type PromptTemplates struct {
 	// inside the console.
 	Success string
 
+	// Result is an optional text/template for the whole line displayed once the value has been entered, in place
+	// of the Success label followed by the value, for example to display a compact summary such as
+	// 	'{{ "✓" | green }} {{ .Label }}: {{ .Value | cyan }}'
+	// It receives the Label of the prompt and the entered Value, which it displays even if the prompt has
+	// a Mask. It isn't used when HideEntered is set, or when a confirm prompt isn't confirmed.
+	Result string
+
 	// Prompt is a text/template for the prompt label when the value is invalid due to an error triggered by
 	// the prompt's validation function.
 	ValidationError string
 
+	// InlineErrors displays the ValidationError on the line of the input, after it, rather than on a line of
+	// its own below the prompt, which keeps the layout of the prompt in place. The error is cut to the width of
+	// the terminal.
+	InlineErrors bool
+
+	// Hint is a text/template for the Hint of the prompt, displayed below the input. It defaults to the hint
+	// in faint text.
+	Hint string
+
+	// Mask is a text/template for the masked characters when Mask or MaskString is set, for example to color
+	// them. The cursor is styled along with the masked character under it. By default the mask is displayed
+	// as is.
+	Mask string
+
+	// QuestionIcon is the icon displayed before the label by the default Prompt and Confirm templates. Defaults
+	// to the IconInitial.
+	QuestionIcon string
+
+	// SuccessIcon is the icon displayed before the label by the default Valid template. Defaults to the
+	// IconGood.
+	SuccessIcon string
+
+	// ErrorIcon is the icon displayed before the label by the default Invalid template. Defaults to the
+	// IconBad.
+	ErrorIcon string
+
 	// FuncMap is a map of helper functions that can be used inside of templates according to the text/template
 	// documentation.
 	//
@@ -106,28 +338,146 @@ type PromptTemplates struct {
 	valid      *template.Template
 	invalid    *template.Template
 	validation *template.Template
+	hint       *template.Template
 	success    *template.Template
+	mask       *template.Template
+	result     *template.Template
+}
+
+// Input is a value entered in a prompt, as returned by RunInput.
+type Input struct {
+	// Value is the entered value. For a command, it is the value without the CommandPrefix, such as "quit" for
+	// ":quit".
+	Value string
+
+	// Command reports whether the value was entered as a command, starting with the CommandPrefix of the prompt.
+	Command bool
+}
+
+// promptRun is the state of a single run of a prompt, shared by its listener and the functions of its templates.
+type promptRun struct {
+	cur   *Cursor
+	timer *countdown
+
+	// attempts is the number of invalid values entered so far.
+	attempts int
+
+	// answers are the answers to the previous questions of the form asking the prompt, if any.
+	answers map[string]string
+
+	// question is the position of the question asked by the prompt among the questions of its form, starting
+	// at 1. showProgress sets whether the default templates display it.
+	question     int
+	questions    int
+	showProgress bool
+
+	// lines is the number of lines left on the screen once the run is over.
+	lines int
+}
+
+// promptResult is the data given to the Result template of a prompt.
+type promptResult struct {
+	Label interface{}
+	Value string
 }
 
 // Run executes the prompt. Its displays the label and default value if any, asking the user to enter a value.
 // Run will keep the prompt alive until it has been canceled from the command prompt or it has received a valid
 // value. It will return the value and an error if any occurred during the prompt's execution.
 func (p *Prompt) Run() (string, error) {
+	return p.RunContext(context.Background())
+}
+
+// RunContext executes the prompt like Run, until the given context is done. The prompt is then cancelled and
+// returns the error of the context.
+func (p *Prompt) RunContext(ctx context.Context) (string, error) {
+	return p.run(ctx, &promptRun{})
+}
+
+// run executes the prompt like RunContext, with the given state for the run.
+func (p *Prompt) run(ctx context.Context, run *promptRun) (string, error) {
 	var err error
 
-	err = p.prepareTemplates()
+	parent := ctx
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+
+	p.accepted = false
+
+	err = p.prepareTemplates(run)
 	if err != nil {
 		return "", err
 	}
 
+	if p.Answers != nil {
+		if value, ok := p.Answers.Lookup(p.Name); ok {
+			return p.answer(ctx, run, value)
+		}
+	}
+
+	stdin := translateStdin(p.Recorder.stdin(p.Stdin, p.Secret || p.mask() != nil))
+
+	var feed *keyFeed
+	if p.Timeout > 0 || p.IdleTimeout > 0 || p.AutoSubmitAt > 0 || p.TabBehavior == TabNextField ||
+		parent.Done() != nil {
+		if stdin == nil {
+			stdin = readline.Stdin
+		}
+		feed = newKeyFeed(stdin)
+		defer feed.Close()
+
+		stdin = readline.NewCancelableStdin(feed)
+	}
+
+	if p.Timeout > 0 {
+		run.timer = newCountdown(p.Timeout)
+		defer run.timer.Stop()
+		go run.timer.Run(feed)
+	}
+
+	// idled is closed once the prompt has timed out for lack of keys
+	var idle *countdown
+	idled := make(chan struct{})
+	if p.IdleTimeout > 0 {
+		idle = newCountdown(p.IdleTimeout)
+		defer idle.Stop()
+		go func() {
+			if idle.Wait() {
+				close(idled)
+				feed.Push(readline.CharInterrupt)
+			}
+		}()
+	}
+
+	if parent.Done() != nil {
+		go func() {
+			<-ctx.Done()
+			if parent.Err() != nil {
+				feed.Push(readline.CharInterrupt)
+			}
+		}()
+	}
+
 	c := &readline.Config{
-		Stdin:          p.Stdin,
-		Stdout:         p.Stdout,
+		Stdin:          stdin,
+		Stdout:         p.Recorder.stdout(p.Stdout),
 		EnableMask:     p.Mask != 0,
 		MaskRune:       p.Mask,
 		HistoryLimit:   -1,
 		VimMode:        p.IsVimMode,
 		UniqueEditLine: true,
+		FuncGetWidth:   p.columns,
+	}
+
+	history := p.history()
+	c.FuncFilterInputRune = func(r rune) (rune, bool) {
+		switch {
+		case r == KeyHistorySearch && history != nil:
+			r = keyHistorySearch
+		case r == readline.CharTab:
+			r = keyTab
+		}
+		return r, true
 	}
 
 	err = c.Init()
@@ -135,20 +485,20 @@ func (p *Prompt) Run() (string, error) {
 		return "", err
 	}
 
+	saveTerminal()
+	defer pushTerminalState(c.Stdout, false)()
+
 	rl, err := readline.NewEx(c)
 	if err != nil {
 		return "", err
 	}
+	defer closeOnPanic(rl)
 	// we're taking over the cursor,  so stop showing it.
 	rl.Write([]byte(hideCursor))
-	sb := screenbuf.New(rl)
+	sb := screenbuf.New(debugFrames(rl, p.DebugWriter))
+	sb.SetMode(p.RedrawMode)
 
-	validFn := func(x string) error {
-		return nil
-	}
-	if p.Validate != nil {
-		validFn = p.Validate
-	}
+	validFn := p.validator(ctx)
 
 	var inputErr error
 	input := p.Default
@@ -157,9 +507,138 @@ func (p *Prompt) Run() (string, error) {
 	}
 	eraseDefault := input != "" && !p.AllowEdit
 	cur := NewCursor(input, p.Pointer, eraseDefault)
+	cur.WordBoundary = p.WordBoundary
+	cur.CaseTransform = p.CaseTransform
+	if p.Secret {
+		defer cur.wipe()
+	}
+	run.cur = &cur
+
+	// edited is set once the user changes the input, even if it ends up back to the default value
+	edited := false
+
+	// search is the ongoing search through the history, if any
+	var search *historySearch
 
 	listen := func(input []rune, pos int, key rune) ([]rune, int, bool) {
+		defer restoreOnPanic(rl)
+
+		if run.timer != nil {
+			if key == keyTick {
+				if run.timer.Expired() {
+					feed.Push(KeyEnter)
+				}
+				input = nil
+				key = 0
+			} else if key != 0 && !run.timer.Expired() {
+				run.timer.Reset()
+			}
+		}
+
+		if idle != nil && key != 0 && key != keyTick {
+			idle.Reset()
+		}
+
+		if search != nil {
+			switch {
+			case key == keyHistorySearch:
+				search.Next()
+			case key == KeyHistoryCancel:
+				cur.Replace(search.original)
+				search = nil
+			case key == KeyBackspace || key == KeyCtrlH:
+				search.Backspace()
+			case unicode.IsPrint(key):
+				search.Type(key)
+			default:
+				// any other key ends the search with the match, handling the key as usual
+				cur.Replace(search.Value())
+				search = nil
+			}
+			if search != nil || key == KeyHistoryCancel {
+				input = nil
+				key = 0
+			}
+		} else if key == keyHistorySearch && cur.quoted {
+			key = KeyHistorySearch
+		} else if key == keyHistorySearch {
+			search = newHistorySearch(history, cur.Get())
+			input = nil
+			key = 0
+		}
+
+		if key == keyTab && cur.quoted {
+			key = readline.CharTab
+		} else if key == keyTab {
+			input = nil
+			key = 0
+			switch p.TabBehavior {
+			case TabInsert:
+				input = []rune{readline.CharTab}
+				key = readline.CharTab
+			case TabNextField:
+				feed.Push(KeyEnter)
+			default:
+				if p.complete(&cur) {
+					edited = true
+				} else {
+					rl.Terminal.Bell()
+				}
+			}
+		}
+
+		if key == KeyPaste && !cur.quoted {
+			if !p.paste(&cur) {
+				rl.Terminal.Bell()
+			}
+			input = nil
+			key = 0
+		}
+
+		if !cur.quoted && unknownKey(key) {
+			input = nil
+			if p.OnUnknownKey != nil {
+				p.OnUnknownKey(key)
+			}
+			key = 0
+		}
+
+		if p.AllowRune != nil {
+			typed := input
+			if cur.quoted && key > 0 && key != KeyEnter {
+				typed = []rune{key}
+			}
+			for _, r := range typed {
+				if !p.AllowRune(p.CaseTransform.apply(r)) {
+					rl.Terminal.Bell()
+					input = nil
+					key = 0
+					break
+				}
+			}
+		}
+
+		if p.MaxLength > 0 && len(input) > 0 {
+			length := len(input)
+			if !cur.erase {
+				length += len(cur.input)
+			}
+
+			if length > p.MaxLength {
+				rl.Terminal.Bell()
+				input = nil
+				key = 0
+			}
+		}
+
+		before := cur.Get()
 		_, _, keepOn := cur.Listen(input, pos, key)
+		if cur.Get() != before {
+			edited = true
+			if p.AutoSubmitAt > 0 && len(cur.input) == p.AutoSubmitAt {
+				feed.Push(KeyEnter)
+			}
+		}
 		err := validFn(cur.Get())
 		var prompt []byte
 
@@ -172,28 +651,95 @@ func (p *Prompt) Run() (string, error) {
 			}
 		}
 
+		// an inline error is displayed on a single line, after the input
+		var inline string
+		if inputErr != nil && p.Templates.InlineErrors {
+			inline = " " + strings.Replace(string(render(p.Templates.validation, inputErr)), "\n", " ", -1)
+			inputErr = nil
+		}
+
+		cols := c.FuncGetWidth()
 		echo := cur.Format()
-		if p.Mask != 0 {
-			echo = cur.FormatMask(p.Mask)
+		if p.DisplayTransform != nil {
+			echo = cur.formatTransform(p.DisplayTransform)
+		}
+		if p.Secret {
+			echo = ""
+		} else if mask := p.mask(); mask != nil && p.MaskWidth > 0 {
+			echo = cur.formatMaskFixed(mask, p.MaskWidth, p.styleMask)
+		} else if mask != nil {
+			echo = cur.formatMask(mask, p.RevealFirst, p.RevealLast, p.styleMask)
+		} else if search != nil {
+			echo = search.Format(cur.Cursor)
+		} else if cols > 0 && p.DisplayTransform == nil {
+			// the input scrolls within the columns left by the label, keeping the last one free so that the
+			// terminal doesn't wrap the line
+			width := cols - lastLineWidth(prompt) - 1
+			if inline != "" {
+				// the error takes the columns it needs after the input, as long as the input keeps half of them
+				reserved := displayWidth([]rune(inline))
+				if reserved > width/2 {
+					reserved = width / 2
+				}
+				width -= reserved
+			}
+			echo = cur.formatScrolled(width)
 		}
 
+		// a label of several lines is displayed by as many lines of the screen, the input following the last one
 		prompt = append(prompt, []byte(echo)...)
+		if inline != "" && cols > 0 {
+			inline = string(truncateWidth([]rune(inline), cols-lastLineWidth(prompt)-1))
+		}
+		prompt = append(prompt, []byte(inline)...)
 		sb.Reset()
-		sb.Write(prompt)
+		for _, line := range bytes.Split(prompt, []byte("\n")) {
+			sb.Write(line)
+		}
+		if p.Hint != "" {
+			for _, line := range bytes.Split(render(p.Templates.hint, p.Hint), []byte("\n")) {
+				sb.Write(line)
+			}
+		}
 		if inputErr != nil {
-			validation := render(p.Templates.validation, inputErr)
-			sb.Write(validation)
+			for _, line := range bytes.Split(render(p.Templates.validation, inputErr), []byte("\n")) {
+				sb.Write(line)
+			}
 			inputErr = nil
 		}
 		sb.Flush()
 		return nil, 0, keepOn
 	}
 
-	c.SetListener(listen)
+	guard := newLineGuard()
+	c.SetListener(guard.listen(listen))
 
 	for {
 		_, err = rl.Readline()
-		inputErr = validFn(cur.Get())
+		guard.wait(err)
+		inputErr = nil
+		if run.timer == nil || !run.timer.Expired() {
+			// the value entered once the timeout expires is accepted even when empty
+			inputErr = p.required(cur.Get())
+		}
+		if inputErr == nil {
+			inputErr = validFn(cur.Get())
+		}
+		if inputErr == nil && err == nil && p.OnSubmit != nil {
+			var value string
+			var submit bool
+			value, submit, inputErr = p.OnSubmit(cur.Get())
+			if inputErr == nil {
+				if value != cur.Get() {
+					edited = true
+				}
+				cur.erase = false
+				cur.Replace(value)
+				if !submit {
+					continue
+				}
+			}
+		}
 		if inputErr == nil {
 			break
 		}
@@ -201,6 +747,12 @@ func (p *Prompt) Run() (string, error) {
 		if err != nil {
 			break
 		}
+
+		run.attempts++
+		if p.MaxRetries > 0 && run.attempts >= p.MaxRetries {
+			err = ErrMaxRetries
+			break
+		}
 	}
 
 	if err != nil {
@@ -213,45 +765,221 @@ func (p *Prompt) Run() (string, error) {
 		if err.Error() == "Interrupt" {
 			err = ErrInterrupt
 		}
+		if parent.Err() != nil {
+			err = parent.Err()
+		}
+		select {
+		case <-idled:
+			err = ErrTimeout
+		default:
+		}
 		sb.Reset()
 		sb.WriteString("")
+		sb.WriteSequence([]byte(showCursor))
 		sb.Flush()
-		rl.Write([]byte(showCursor))
 		rl.Close()
 		return "", err
 	}
 
+	prompt, err := p.result(&cur)
+
+	sb.WriteSequence([]byte(showCursor))
+	if p.HideEntered {
+		clearScreen(sb)
+	} else {
+		sb.Reset()
+		for _, line := range bytes.Split(prompt, []byte("\n")) {
+			sb.Write(line)
+		}
+		sb.Flush()
+	}
+	run.lines = sb.Height()
+
+	rl.Close()
+
+	p.accepted = err == nil && (p.Default != "" || p.IsConfirm && p.ConfirmDefault) && !edited
+
+	if err == nil {
+		if history != nil {
+			history.Add(cur.Get())
+		}
+		err = copyToClipboard(p.CopyToClipboard, cur.Get())
+	}
+
+	return cur.Get(), err
+}
+
+// RunInput executes the prompt like Run, telling the commands starting with the CommandPrefix apart from the
+// input.
+func (p *Prompt) RunInput() (Input, error) {
+	value, err := p.Run()
+	if p.isCommand(value) {
+		return Input{Value: strings.TrimPrefix(value, p.CommandPrefix), Command: true}, err
+	}
+	return Input{Value: value}, err
+}
+
+// isCommand reports whether the value is a command, starting with the CommandPrefix.
+func (p *Prompt) isCommand(value string) bool {
+	return p.CommandPrefix != "" && !p.IsConfirm && strings.HasPrefix(value, p.CommandPrefix)
+}
+
+// unknownKey reports whether the prompt has no use for the given key, which is then ignored rather than inserted.
+func unknownKey(key rune) bool {
+	switch key {
+	case 0, readline.CharTab, readline.CharCtrlJ, readline.CharDelete, readline.CharInterrupt:
+		// tab is only left to be inserted, the others end the line
+		return false
+	}
+	return !unicode.IsPrint(key) && !listens(key)
+}
+
+// lastLineWidth returns the number of columns taken by the last line of the rendered text.
+func lastLineWidth(text []byte) int {
+	if i := bytes.LastIndexByte(text, '\n'); i >= 0 {
+		text = text[i+1:]
+	}
+	return displayWidth([]rune(string(text)))
+}
+
+// history returns the History of the prompt, or nil if it doesn't use one.
+func (p *Prompt) history() *History {
+	if p.IsConfirm || p.Secret || p.mask() != nil {
+		return nil
+	}
+	return p.History
+}
+
+// AcceptedDefault reports whether the value returned by the last run of the prompt is its Default, accepted as is
+// by pressing enter. It is false if the user typed or edited the value, even if it ends up equal to the Default,
+// or if the value came from the Answers of the prompt. For a confirm prompt, it reports whether the user
+// answered by pressing enter alone.
+func (p *Prompt) AcceptedDefault() bool {
+	return p.accepted
+}
+
+// validator returns the function validating the input, combining Validate or ValidateContext with the checks
+// of the other options.
+func (p *Prompt) validator(ctx context.Context) func(string) error {
+	validFn := func(x string) error {
+		return nil
+	}
+	if p.Validate != nil {
+		validFn = p.Validate
+	}
+	if p.ValidateContext != nil {
+		validFn = func(x string) error {
+			return p.ValidateContext(ctx, x)
+		}
+	}
+
+	if p.MinLength > 0 {
+		validate := validFn
+		validFn = func(x string) error {
+			if utf8.RuneCountInString(x) < p.MinLength {
+				return fmt.Errorf("must be at least %d characters long", p.MinLength)
+			}
+			return validate(x)
+		}
+	}
+
+	if p.CommandPrefix != "" {
+		validate := validFn
+		validFn = func(x string) error {
+			if p.isCommand(x) {
+				return nil
+			}
+			return validate(x)
+		}
+	}
+
+	if p.AllowEmpty && !p.IsConfirm {
+		validate := validFn
+		validFn = func(x string) error {
+			if x == "" {
+				return nil
+			}
+			return validate(x)
+		}
+	}
+
+	return validFn
+}
+
+// required refuses an empty value once it is entered, unless the prompt has a Default or AllowEmpty is set.
+// It isn't part of the validator so that the prompt doesn't start in the invalid state.
+func (p *Prompt) required(x string) error {
+	if x == "" && !p.IsConfirm && !p.AllowEmpty && p.Default == "" {
+		return errors.New("a value is required")
+	}
+	return nil
+}
+
+// result returns the line displayed once the value of the cursor has been entered, along with ErrAbort if the
+// prompt is a confirm one and the user didn't confirm.
+func (p *Prompt) result(cur *Cursor) ([]byte, error) {
+	var err error
+
 	echo := cur.Get()
-	if p.Mask != 0 {
-		echo = cur.GetMask(p.Mask)
+	if p.DisplayTransform != nil {
+		echo = p.DisplayTransform(echo)
+	}
+	if p.Secret {
+		echo = ""
+	} else if mask := p.mask(); mask != nil && p.MaskWidth > 0 {
+		echo = styleRunes(fixedMask(mask, p.MaskWidth), p.styleMask)
+	} else if mask != nil {
+		echo = cur.getMask(mask, p.RevealFirst, p.RevealLast, p.styleMask)
 	}
 
 	prompt := render(p.Templates.success, p.Label)
 	prompt = append(prompt, []byte(echo)...)
+	if p.Templates.result != nil {
+		prompt = render(p.Templates.result, promptResult{Label: p.Label, Value: cur.Get()})
+	}
 
 	if p.IsConfirm {
-		lowerDefault := strings.ToLower(p.Default)
-		if strings.ToLower(cur.Get()) != "y" && (lowerDefault != "y" || (lowerDefault == "y" && cur.Get() != "")) {
+		if strings.ToLower(cur.Get()) != "y" && (!p.confirmDefault() || cur.Get() != "") {
 			prompt = render(p.Templates.invalid, p.Label)
 			err = ErrAbort
 		}
 	}
 
-	if p.HideEntered {
-		clearScreen(sb)
-	} else {
-		sb.Reset()
-		sb.Write(prompt)
-		sb.Flush()
+	return prompt, err
+}
+
+// confirmDefault reports whether a confirm prompt answers yes when the user presses enter alone.
+func (p *Prompt) confirmDefault() bool {
+	return p.ConfirmDefault || strings.ToLower(p.Default) == "y"
+}
+
+// answer enters the given value from the Answers of the prompt without asking the user. The value goes through
+// the same validation as a typed one, and an invalid value is returned as an error.
+func (p *Prompt) answer(ctx context.Context, run *promptRun, value string) (string, error) {
+	err := p.required(value)
+	if err == nil {
+		err = p.validator(ctx)(value)
+	}
+	if err != nil {
+		return "", err
 	}
 
-	rl.Write([]byte(showCursor))
-	rl.Close()
+	cur := NewCursor(value, p.Pointer, false)
+	prompt, err := p.result(&cur)
 
-	return cur.Get(), err
+	if !p.HideEntered {
+		debugFrames(p.Recorder.stdout(p.Stdout), p.DebugWriter).Write(append(prompt, '\n'))
+		run.lines = bytes.Count(prompt, []byte("\n")) + 1
+	}
+
+	if err == nil {
+		err = copyToClipboard(p.CopyToClipboard, value)
+	}
+
+	return value, err
 }
 
-func (p *Prompt) prepareTemplates() error {
+func (p *Prompt) prepareTemplates(run *promptRun) error {
 	tpls := p.Templates
 	if tpls == nil {
 		tpls = &PromptTemplates{}
@@ -261,18 +989,31 @@ func (p *Prompt) prepareTemplates() error {
 		tpls.FuncMap = FuncMap
 	}
 
+	if tpls.QuestionIcon == "" {
+		tpls.QuestionIcon = IconInitial
+	}
+
+	if tpls.SuccessIcon == "" {
+		tpls.SuccessIcon = IconGood
+	}
+
+	if tpls.ErrorIcon == "" {
+		tpls.ErrorIcon = IconBad
+	}
+
 	bold := Styler(FGBold)
 
 	if p.IsConfirm {
 		if tpls.Confirm == "" {
 			confirm := "y/N"
-			if strings.ToLower(p.Default) == "y" {
+			if p.confirmDefault() {
 				confirm = "Y/n"
 			}
-			tpls.Confirm = fmt.Sprintf(`{{ "%s" | bold }} {{ . | bold }}? {{ "[%s]" | faint }} `, IconInitial, confirm)
+			tpls.Confirm = fmt.Sprintf(`{{ %q | bold }} {{ . | bold }}? {{ "[%s]" | faint }}%s `, tpls.QuestionIcon,
+				confirm, p.statusTemplate(run))
 		}
 
-		tpl, err := template.New("").Funcs(tpls.FuncMap).Parse(tpls.Confirm)
+		tpl, err := template.New("").Funcs(tpls.FuncMap).Funcs(p.inputFuncs(run)).Parse(tpls.Confirm)
 		if err != nil {
 			return err
 		}
@@ -280,10 +1021,11 @@ func (p *Prompt) prepareTemplates() error {
 		tpls.prompt = tpl
 	} else {
 		if tpls.Prompt == "" {
-			tpls.Prompt = fmt.Sprintf("%s {{ . | bold }}%s ", bold(IconInitial), bold(":"))
+			tpls.Prompt = fmt.Sprintf("{{ %q | bold }} {{ . | bold }}%s%s ", tpls.QuestionIcon, p.statusTemplate(run),
+				bold(":"))
 		}
 
-		tpl, err := template.New("").Funcs(tpls.FuncMap).Parse(tpls.Prompt)
+		tpl, err := template.New("").Funcs(tpls.FuncMap).Funcs(p.inputFuncs(run)).Parse(tpls.Prompt)
 		if err != nil {
 			return err
 		}
@@ -292,10 +1034,10 @@ func (p *Prompt) prepareTemplates() error {
 	}
 
 	if tpls.Valid == "" {
-		tpls.Valid = fmt.Sprintf("%s {{ . | bold }}%s ", bold(IconGood), bold(":"))
+		tpls.Valid = fmt.Sprintf("{{ %q | bold }} {{ . | bold }}%s%s ", tpls.SuccessIcon, p.statusTemplate(run), bold(":"))
 	}
 
-	tpl, err := template.New("").Funcs(tpls.FuncMap).Parse(tpls.Valid)
+	tpl, err := template.New("").Funcs(tpls.FuncMap).Funcs(p.inputFuncs(run)).Parse(tpls.Valid)
 	if err != nil {
 		return err
 	}
@@ -303,10 +1045,10 @@ func (p *Prompt) prepareTemplates() error {
 	tpls.valid = tpl
 
 	if tpls.Invalid == "" {
-		tpls.Invalid = fmt.Sprintf("%s {{ . | bold }}%s ", bold(IconBad), bold(":"))
+		tpls.Invalid = fmt.Sprintf("{{ %q | bold }} {{ . | bold }}%s%s ", tpls.ErrorIcon, p.statusTemplate(run), bold(":"))
 	}
 
-	tpl, err = template.New("").Funcs(tpls.FuncMap).Parse(tpls.Invalid)
+	tpl, err = template.New("").Funcs(tpls.FuncMap).Funcs(p.inputFuncs(run)).Parse(tpls.Invalid)
 	if err != nil {
 		return err
 	}
@@ -314,28 +1056,169 @@ func (p *Prompt) prepareTemplates() error {
 	tpls.invalid = tpl
 
 	if tpls.ValidationError == "" {
-		tpls.ValidationError = `{{ ">>" | red }} {{ . | red }}`
+		tpls.ValidationError = `{{ ">>" | red }} {{ . | red }}` +
+			`{{ with attemptsLeft }} {{ if eq . 1 }}(1 try left){{ else }}({{ . }} tries left){{ end }}{{ end }}`
 	}
 
-	tpl, err = template.New("").Funcs(tpls.FuncMap).Parse(tpls.ValidationError)
+	tpl, err = template.New("").Funcs(tpls.FuncMap).Funcs(p.inputFuncs(run)).Parse(tpls.ValidationError)
 	if err != nil {
 		return err
 	}
 
 	tpls.validation = tpl
 
+	if tpls.Hint == "" {
+		tpls.Hint = `{{ . | faint }}`
+	}
+
+	tpl, err = template.New("").Funcs(tpls.FuncMap).Funcs(p.inputFuncs(run)).Parse(tpls.Hint)
+	if err != nil {
+		return err
+	}
+
+	tpls.hint = tpl
+
 	if tpls.Success == "" {
 		tpls.Success = fmt.Sprintf("{{ . | faint }}%s ", Styler(FGFaint)(":"))
 	}
 
-	tpl, err = template.New("").Funcs(tpls.FuncMap).Parse(tpls.Success)
+	tpl, err = template.New("").Funcs(tpls.FuncMap).Funcs(p.inputFuncs(run)).Parse(tpls.Success)
 	if err != nil {
 		return err
 	}
 
 	tpls.success = tpl
 
+	if tpls.Result != "" {
+		tpl, err = template.New("").Funcs(tpls.FuncMap).Funcs(p.inputFuncs(run)).Parse(tpls.Result)
+		if err != nil {
+			return err
+		}
+
+		tpls.result = tpl
+	}
+
+	if tpls.Mask != "" {
+		tpl, err = template.New("").Funcs(tpls.FuncMap).Funcs(p.inputFuncs(run)).Parse(tpls.Mask)
+		if err != nil {
+			return err
+		}
+
+		tpls.mask = tpl
+	}
+
 	p.Templates = tpls
 
 	return nil
 }
+
+// paste inserts the content of the clipboard at the position of the cursor, without the runes AllowRune rejects
+// and up to the MaxLength of the prompt.
+// It returns false if the clipboard can't be read.
+func (p *Prompt) paste(cur *Cursor) bool {
+	text, err := PasteFromClipboard()
+	if err != nil {
+		return false
+	}
+
+	if cur.erase {
+		cur.erase = false
+		cur.Replace("")
+	}
+
+	r := []rune(singleLine(text))
+	if p.AllowRune != nil {
+		allowed := r[:0]
+		for _, c := range r {
+			if p.AllowRune(p.CaseTransform.apply(c)) {
+				allowed = append(allowed, c)
+			}
+		}
+		r = allowed
+	}
+	if p.MaxLength > 0 {
+		room := p.MaxLength - len(cur.input)
+		if room < 0 {
+			room = 0
+		}
+		if len(r) > room {
+			r = r[:room]
+		}
+	}
+
+	cur.Update(string(r))
+	return true
+}
+
+// mask returns the runes masking the input, MaskString taking precedence over Mask, or nil if the input isn't
+// masked.
+func (p *Prompt) mask() []rune {
+	if p.MaskString != "" {
+		return []rune(p.MaskString)
+	}
+	if p.Mask != 0 {
+		return []rune{p.Mask}
+	}
+	return nil
+}
+
+// styleMask renders the masked characters with the mask template, if any.
+func (p *Prompt) styleMask(mask string) string {
+	if p.Templates.mask == nil || mask == "" {
+		return mask
+	}
+	return string(render(p.Templates.mask, mask))
+}
+
+// statusTemplate returns the part of the default templates displaying the position of the question asked by the
+// prompt in its form and the time left before the prompt times out, if any.
+func (p *Prompt) statusTemplate(run *promptRun) string {
+	var status string
+	if run.showProgress {
+		status += ` {{ printf "(%d/%d)" question questions | faint }}`
+	}
+	if p.Timeout > 0 {
+		status += ` {{ remaining | printf "(auto-accept in %v)" | faint }}`
+	}
+	return status
+}
+
+// inputFuncs returns the template functions giving access to the state of the given run of the prompt inside the
+// templates.
+func (p *Prompt) inputFuncs(run *promptRun) template.FuncMap {
+	return template.FuncMap{
+		"length": func() int {
+			if run.cur == nil {
+				return 0
+			}
+			return len(run.cur.input)
+		},
+		"remaining": func() time.Duration {
+			if run.timer == nil {
+				return 0
+			}
+			return run.timer.Remaining()
+		},
+		"answers": func() map[string]string {
+			return run.answers
+		},
+		"question": func() int {
+			return run.question
+		},
+		"questions": func() int {
+			return run.questions
+		},
+		"attempts": func() int {
+			return run.attempts
+		},
+		"data": func(key string) interface{} {
+			return p.TemplateData[key]
+		},
+		"attemptsLeft": func() int {
+			if p.MaxRetries <= 0 {
+				return 0
+			}
+			return p.MaxRetries - run.attempts
+		},
+	}
+}
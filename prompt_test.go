@@ -0,0 +1,1399 @@
+package promptui
+
+import (
+	"context"
+	"errors"
+	"io"
+	"io/ioutil"
+	"reflect"
+	"regexp"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+	"unicode"
+)
+
+// scriptedPrompt sets up the prompt to read the given keys as if they were typed by the user.
+func scriptedPrompt(p *Prompt, keys string) *nopWriteCloser {
+	out := &nopWriteCloser{}
+	p.Stdin = scriptedStdin(keys)
+	p.Stdout = out
+	return out
+}
+
+func TestPromptSecret(t *testing.T) {
+	p := Prompt{
+		Label:  "Password",
+		Secret: true,
+		Mask:   '*',
+	}
+	out := scriptedPrompt(&p, "hunter2\r")
+
+	value, err := p.Run()
+	if err != nil {
+		t.Fatalf("Unexpected error %v", err)
+	}
+
+	if value != "hunter2" {
+		t.Errorf("Expected %q, got %q", "hunter2", value)
+	}
+
+	for _, echo := range []string{"hu", "*", "█"} {
+		if strings.Contains(out.String(), echo) {
+			t.Errorf("Expected output not to contain %q, got %q", echo, out.String())
+		}
+	}
+}
+
+func TestPromptMaskString(t *testing.T) {
+	p := Prompt{
+		Label:      "Password",
+		MaskString: "•◦",
+		Templates: &PromptTemplates{
+			Mask: "<{{ . }}>",
+		},
+	}
+	out := scriptedPrompt(&p, "abc\r")
+
+	value, err := p.Run()
+	if err != nil {
+		t.Fatalf("Unexpected error %v", err)
+	}
+
+	if value != "abc" {
+		t.Errorf("Expected %q, got %q", "abc", value)
+	}
+
+	for _, echo := range []string{"<•◦•>█", "<•◦•>"} {
+		if !strings.Contains(out.String(), echo) {
+			t.Errorf("Expected output to contain %q, got %q", echo, out.String())
+		}
+	}
+
+	if strings.Contains(out.String(), "abc") {
+		t.Errorf("Expected output not to contain the input, got %q", out.String())
+	}
+}
+
+func TestPromptMaskReveal(t *testing.T) {
+	p := Prompt{
+		Label:      "Card number",
+		Mask:       '*',
+		RevealLast: 4,
+	}
+	out := scriptedPrompt(&p, "41111234\r")
+
+	value, err := p.Run()
+	if err != nil {
+		t.Fatalf("Unexpected error %v", err)
+	}
+
+	if value != "41111234" {
+		t.Errorf("Expected %q, got %q", "41111234", value)
+	}
+
+	for _, echo := range []string{"**1112█", "****1234█", "****1234\n"} {
+		if !strings.Contains(out.String(), echo) {
+			t.Errorf("Expected output to contain %q, got %q", echo, out.String())
+		}
+	}
+}
+
+func TestPromptMaskWidth(t *testing.T) {
+	p := Prompt{
+		Label:     "Password",
+		Mask:      '*',
+		MaskWidth: 8,
+	}
+	out := scriptedPrompt(&p, "abc\r")
+
+	value, err := p.Run()
+	if err != nil {
+		t.Fatalf("Unexpected error %v", err)
+	}
+
+	if value != "abc" {
+		t.Errorf("Expected %q, got %q", "abc", value)
+	}
+
+	if !strings.Contains(out.String(), "********\n") {
+		t.Errorf("Expected output to contain the fixed mask, got %q", out.String())
+	}
+
+	for _, echo := range []string{"***█", "*█"} {
+		if strings.Contains(out.String(), " "+echo) {
+			t.Errorf("Expected output not to contain %q, got %q", echo, out.String())
+		}
+	}
+}
+
+func TestPromptLength(t *testing.T) {
+	t.Run("when typing past the maximum length", func(t *testing.T) {
+		p := Prompt{
+			Label:     "PIN",
+			MaxLength: 4,
+		}
+		out := scriptedPrompt(&p, "123456\x7f9\r")
+
+		value, err := p.Run()
+		if err != nil {
+			t.Fatalf("Unexpected error %v", err)
+		}
+
+		if value != "1239" {
+			t.Errorf("Expected %q, got %q", "1239", value)
+		}
+
+		if !strings.Contains(out.String(), "\a") {
+			t.Errorf("Expected the bell to ring, got %q", out.String())
+		}
+	})
+
+	t.Run("when replacing a default longer than the maximum length", func(t *testing.T) {
+		p := Prompt{
+			Label:     "PIN",
+			Default:   "0000",
+			MaxLength: 4,
+		}
+		scriptedPrompt(&p, "12\r")
+
+		value, err := p.Run()
+		if err != nil {
+			t.Fatalf("Unexpected error %v", err)
+		}
+
+		if value != "12" {
+			t.Errorf("Expected %q, got %q", "12", value)
+		}
+	})
+
+	t.Run("when entering less than the minimum length", func(t *testing.T) {
+		p := Prompt{
+			Label:     "PIN",
+			MinLength: 4,
+		}
+		out := scriptedPrompt(&p, "12\r34\r")
+
+		value, err := p.Run()
+		if err != nil {
+			t.Fatalf("Unexpected error %v", err)
+		}
+
+		if value != "1234" {
+			t.Errorf("Expected %q, got %q", "1234", value)
+		}
+
+		exp := "must be at least 4 characters long"
+		if !strings.Contains(out.String(), exp) {
+			t.Errorf("Expected output to contain %q, got %q", exp, out.String())
+		}
+	})
+
+	t.Run("when displaying a counter", func(t *testing.T) {
+		p := Prompt{
+			Label: "PIN",
+			Templates: &PromptTemplates{
+				Valid: "{{ . }} ({{ length }}/4) ",
+			},
+		}
+		out := scriptedPrompt(&p, "12\r")
+
+		_, err := p.Run()
+		if err != nil {
+			t.Fatalf("Unexpected error %v", err)
+		}
+
+		for _, exp := range []string{"PIN (0/4)", "PIN (1/4)", "PIN (2/4)"} {
+			if !strings.Contains(out.String(), exp) {
+				t.Errorf("Expected output to contain %q, got %q", exp, out.String())
+			}
+		}
+	})
+}
+
+func TestPromptAllowRune(t *testing.T) {
+	tcs := []struct {
+		scenario string
+		keys     string
+		expect   string
+	}{
+		{scenario: "ignore the rejected runes", keys: "1a2-3\r", expect: "123"},
+		{scenario: "ignore the rejected quoted keys", keys: "1\x16\t2\r", expect: "12"},
+		{scenario: "keep editing", keys: "12x\x7f3\r", expect: "13"},
+	}
+
+	for _, tc := range tcs {
+		t.Run(tc.scenario, func(t *testing.T) {
+			p := Prompt{
+				Label:     "PIN",
+				AllowRune: unicode.IsDigit,
+			}
+			out := scriptedPrompt(&p, tc.keys)
+
+			value, err := p.Run()
+			if err != nil {
+				t.Fatalf("Unexpected error %v", err)
+			}
+
+			if value != tc.expect {
+				t.Errorf("Expected %q, got %q", tc.expect, value)
+			}
+
+			if !strings.Contains(out.String(), "\a") {
+				t.Errorf("Expected the bell to ring, got %q", out.String())
+			}
+		})
+	}
+
+	t.Run("when displaying the input", func(t *testing.T) {
+		p := Prompt{
+			Label:     "PIN",
+			AllowRune: unicode.IsDigit,
+		}
+		out := scriptedPrompt(&p, "1a\r")
+
+		_, err := p.Run()
+		if err != nil {
+			t.Fatalf("Unexpected error %v", err)
+		}
+
+		if strings.Contains(out.String(), "1a") {
+			t.Errorf("Expected the rejected rune not to be displayed, got %q", out.String())
+		}
+	})
+}
+
+func TestPromptCaseTransform(t *testing.T) {
+	hex := func(r rune) bool {
+		return unicode.IsDigit(r) || r >= 'A' && r <= 'F'
+	}
+
+	tcs := []struct {
+		scenario string
+		prompt   Prompt
+		keys     string
+		expect   string
+	}{
+		{scenario: "upper", prompt: Prompt{CaseTransform: CaseUpper}, keys: "us\x7fk\r", expect: "UK"},
+		{scenario: "lower", prompt: Prompt{CaseTransform: CaseLower}, keys: "FF00\x01\x06Aa\r", expect: "faaf00"},
+		{scenario: "allow the transformed runes", prompt: Prompt{CaseTransform: CaseUpper, AllowRune: hex}, keys: "ffg0\r", expect: "FF0"},
+		{scenario: "keep the default", prompt: Prompt{CaseTransform: CaseUpper, Default: "fr", AllowEdit: true}, keys: "a\r", expect: "frA"},
+	}
+
+	for _, tc := range tcs {
+		t.Run(tc.scenario, func(t *testing.T) {
+			p := tc.prompt
+			p.Label = "Code"
+			scriptedPrompt(&p, tc.keys)
+
+			value, err := p.Run()
+			if err != nil {
+				t.Fatalf("Unexpected error %v", err)
+			}
+
+			if value != tc.expect {
+				t.Errorf("Expected %q, got %q", tc.expect, value)
+			}
+		})
+	}
+
+	t.Run("when displaying the input", func(t *testing.T) {
+		p := Prompt{
+			Label:         "Code",
+			CaseTransform: CaseUpper,
+			Pointer:       NoCursor,
+		}
+		out := scriptedPrompt(&p, "us\r")
+
+		_, err := p.Run()
+		if err != nil {
+			t.Fatalf("Unexpected error %v", err)
+		}
+
+		for _, exp := range []string{" U\n", " US\n"} {
+			if !strings.Contains(out.String(), exp) {
+				t.Errorf("Expected output to contain %q, got %q", exp, out.String())
+			}
+		}
+	})
+}
+
+func TestPromptWords(t *testing.T) {
+	p := Prompt{
+		Label:        "Path",
+		WordBoundary: PunctWordBoundary,
+	}
+	scriptedPrompt(&p, "/usr/local/bin\x17\x17\x17lib\x1bbX\x1bf/\r")
+
+	value, err := p.Run()
+	if err != nil {
+		t.Fatalf("Unexpected error %v", err)
+	}
+
+	if value != "/usr/Xlib/" {
+		t.Errorf("Expected %q, got %q", "/usr/Xlib/", value)
+	}
+}
+
+func TestPromptLineStartEnd(t *testing.T) {
+	p := Prompt{Label: "Name"}
+	scriptedPrompt(&p, "bc\x01a\x05d\r")
+
+	value, err := p.Run()
+	if err != nil {
+		t.Fatalf("Unexpected error %v", err)
+	}
+
+	if value != "abcd" {
+		t.Errorf("Expected %q, got %q", "abcd", value)
+	}
+}
+
+func TestPromptQuotedInsert(t *testing.T) {
+	p := Prompt{Label: "Separator"}
+	scriptedPrompt(&p, "a\x16\tb\x16\x01\x16\x16\r")
+
+	value, err := p.Run()
+	if err != nil {
+		t.Fatalf("Unexpected error %v", err)
+	}
+
+	if value != "a\tb\x01\x16" {
+		t.Errorf("Expected %q, got %q", "a\tb\x01\x16", value)
+	}
+}
+
+func TestPromptTabBehavior(t *testing.T) {
+	completer := func(input string) []string {
+		return []string{"status", "stash", "show"}
+	}
+
+	tcs := []struct {
+		scenario string
+		prompt   Prompt
+		keys     string
+		expect   string
+	}{
+		{scenario: "ignore tab by default", prompt: Prompt{}, keys: "a\tb\r", expect: "ab"},
+		{scenario: "complete the common prefix", prompt: Prompt{Completer: completer}, keys: "st\tt\t\r", expect: "status"},
+		{scenario: "leave an ambiguous input as is", prompt: Prompt{Completer: completer}, keys: "s\t\r", expect: "s"},
+		{scenario: "complete the default value", prompt: Prompt{Default: "git", Completer: completer}, keys: "\t\r", expect: "s"},
+		{scenario: "insert a tab", prompt: Prompt{TabBehavior: TabInsert}, keys: "a\tb\r", expect: "a\tb"},
+		{scenario: "enter the input", prompt: Prompt{TabBehavior: TabNextField}, keys: "ab\t", expect: "ab"},
+		{scenario: "insert a quoted tab", prompt: Prompt{TabBehavior: TabNextField}, keys: "a\x16\tb\r", expect: "a\tb"},
+	}
+
+	for _, tc := range tcs {
+		t.Run(tc.scenario, func(t *testing.T) {
+			p := tc.prompt
+			p.Label = "Command"
+			scriptedPrompt(&p, tc.keys)
+
+			value, err := p.Run()
+			if err != nil {
+				t.Fatalf("Unexpected error %v", err)
+			}
+
+			if value != tc.expect {
+				t.Errorf("Expected %q, got %q", tc.expect, value)
+			}
+		})
+	}
+}
+
+func TestPromptDisplayTransform(t *testing.T) {
+	group := func(input string) string {
+		var out []rune
+		for i, r := range []rune(input) {
+			if i > 0 && i%4 == 0 {
+				out = append(out, ' ')
+			}
+			out = append(out, r)
+		}
+		return string(out)
+	}
+
+	p := Prompt{
+		Label:            "Card",
+		Pointer:          PipeCursor,
+		DisplayTransform: group,
+	}
+	out := scriptedPrompt(&p, "123456\x1b[D\x1b[D\x1b[D\r")
+
+	value, err := p.Run()
+	if err != nil {
+		t.Fatalf("Unexpected error %v", err)
+	}
+
+	if value != "123456" {
+		t.Errorf("Expected %q, got %q", "123456", value)
+	}
+
+	for _, echo := range []string{"1234 56|", "123|4 56", "1234 56"} {
+		if !strings.Contains(out.String(), echo) {
+			t.Errorf("Expected output to contain %q, got %q", echo, out.String())
+		}
+	}
+}
+
+func TestPromptScroll(t *testing.T) {
+	p := Prompt{
+		Label: "Name",
+		Templates: &PromptTemplates{
+			Prompt:  "{{ . }}: ",
+			Valid:   "{{ . }}: ",
+			Success: "{{ . }}: ",
+		},
+		columns: func() int { return 20 },
+	}
+	out := scriptedPrompt(&p, "abcdefghijklmnop\x01\r")
+
+	value, err := p.Run()
+	if err != nil {
+		t.Fatalf("Unexpected error %v", err)
+	}
+
+	if value != "abcdefghijklmnop" {
+		t.Errorf("Expected %q, got %q", "abcdefghijklmnop", value)
+	}
+
+	for _, exp := range []string{"Name: ‹fghijklmnop█", "Name: █bcdefghijkl›", "Name: abcdefghijklmnop\n"} {
+		if !strings.Contains(out.String(), exp) {
+			t.Errorf("Expected output to contain %q, got %q", exp, out.String())
+		}
+	}
+}
+
+func TestPromptInlineErrors(t *testing.T) {
+	cases := []struct {
+		name    string
+		columns int
+		exp     string
+	}{
+		{name: "displays the error after the input", columns: 40, exp: "Name: ab█ too short\n"},
+		{name: "cuts the error to the width", columns: 16, exp: "Name: ab█ too s\n"},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			columns := tc.columns
+			p := Prompt{
+				Label: "Name",
+				Templates: &PromptTemplates{
+					Prompt:          "{{ . }}: ",
+					Valid:           "{{ . }}: ",
+					Invalid:         "{{ . }}: ",
+					Success:         "{{ . }}: ",
+					ValidationError: "{{ . }}",
+					InlineErrors:    true,
+				},
+				Validate: func(input string) error {
+					if len(input) < 3 {
+						return errors.New("too short")
+					}
+					return nil
+				},
+				columns: func() int { return columns },
+			}
+			out := scriptedPrompt(&p, "ab\rc\r")
+
+			value, err := p.Run()
+			if err != nil {
+				t.Fatalf("Unexpected error %v", err)
+			}
+
+			if value != "abc" {
+				t.Errorf("Expected %q, got %q", "abc", value)
+			}
+
+			if !strings.Contains(out.String(), tc.exp) {
+				t.Errorf("Expected output to contain %q, got %q", tc.exp, out.String())
+			}
+		})
+	}
+}
+
+func TestPromptTimeout(t *testing.T) {
+	t.Run("accepts the default value", func(t *testing.T) {
+		p := Prompt{
+			Label:   "Name",
+			Default: "gopher",
+			Timeout: 200 * time.Millisecond,
+		}
+		out := scriptedPrompt(&p, "")
+
+		value, err := p.Run()
+		if err != nil {
+			t.Fatalf("Unexpected error %v", err)
+		}
+
+		if value != "gopher" {
+			t.Errorf("Expected %q, got %q", "gopher", value)
+		}
+
+		if !strings.Contains(out.String(), "(auto-accept in 1s)") {
+			t.Errorf("Expected output to contain the countdown, got %q", out.String())
+		}
+	})
+
+	t.Run("enters an empty value without a default", func(t *testing.T) {
+		p := Prompt{
+			Label:   "Name",
+			Timeout: 200 * time.Millisecond,
+		}
+		scriptedPrompt(&p, "")
+
+		value, err := p.Run()
+		if err != nil {
+			t.Fatalf("Unexpected error %v", err)
+		}
+
+		if value != "" {
+			t.Errorf("Expected an empty value, got %q", value)
+		}
+	})
+
+	t.Run("exposes the time left to templates", func(t *testing.T) {
+		p := Prompt{
+			Label:   "Name",
+			Timeout: 200 * time.Millisecond,
+			Templates: &PromptTemplates{
+				Prompt: "{{ . }} [{{ remaining }}] ",
+				Valid:  "{{ . }} [{{ remaining }}] ",
+			},
+		}
+		out := scriptedPrompt(&p, "ab")
+
+		value, err := p.Run()
+		if err != nil {
+			t.Fatalf("Unexpected error %v", err)
+		}
+
+		if value != "ab" {
+			t.Errorf("Expected %q, got %q", "ab", value)
+		}
+
+		if !strings.Contains(out.String(), "Name [1s] ab") {
+			t.Errorf("Expected output to contain the time left, got %q", out.String())
+		}
+	})
+
+	t.Run("doesn't outlive its run", func(t *testing.T) {
+		p := Prompt{
+			Label:   "Name",
+			Default: "gopher",
+			Timeout: 200 * time.Millisecond,
+		}
+		scriptedPrompt(&p, "")
+
+		_, err := p.Run()
+		if err != nil {
+			t.Fatalf("Unexpected error %v", err)
+		}
+
+		// the key code of the countdown ticks, typed by the user once the prompt runs without a timeout
+		p.Timeout = 0
+		scriptedPrompt(&p, "\x1e\r")
+
+		value, err := p.Run()
+		if err != nil {
+			t.Fatalf("Unexpected error %v", err)
+		}
+
+		if value != "gopher" {
+			t.Errorf("Expected %q, got %q", "gopher", value)
+		}
+	})
+}
+
+// pacedReader reads its keys one at a time, waiting before each one.
+type pacedReader struct {
+	keys  string
+	delay time.Duration
+}
+
+func (r *pacedReader) Read(p []byte) (int, error) {
+	time.Sleep(r.delay)
+	if r.keys == "" {
+		return 0, io.EOF
+	}
+	n := copy(p, r.keys[:1])
+	r.keys = r.keys[n:]
+	return n, nil
+}
+
+func TestPromptIdleTimeout(t *testing.T) {
+	t.Run("fails without entering the default value", func(t *testing.T) {
+		p := Prompt{
+			Label:       "Name",
+			Default:     "gopher",
+			IdleTimeout: 100 * time.Millisecond,
+		}
+		out := scriptedPrompt(&p, "ab")
+
+		value, err := p.Run()
+		if err != ErrTimeout {
+			t.Fatalf("Expected ErrTimeout, got %v", err)
+		}
+
+		if value != "" {
+			t.Errorf("Expected no value, got %q", value)
+		}
+
+		if !strings.HasSuffix(out.String(), showCursor) {
+			t.Errorf("Expected the cursor to be shown again, got %q", out.String())
+		}
+	})
+
+	t.Run("restarts on each key", func(t *testing.T) {
+		p := Prompt{
+			Label:       "Name",
+			IdleTimeout: 150 * time.Millisecond,
+			Stdin:       ioutil.NopCloser(&pacedReader{keys: "abc\r", delay: 60 * time.Millisecond}),
+			Stdout:      &nopWriteCloser{},
+		}
+
+		value, err := p.Run()
+		if err != nil {
+			t.Fatalf("Unexpected error %v", err)
+		}
+
+		if value != "abc" {
+			t.Errorf("Expected %q, got %q", "abc", value)
+		}
+	})
+}
+
+func TestPromptAllowEmpty(t *testing.T) {
+	required := func(input string) error {
+		if input == "" {
+			return errors.New("required")
+		}
+		return nil
+	}
+
+	t.Run("when allowed", func(t *testing.T) {
+		p := Prompt{
+			Label:      "Nickname",
+			Validate:   required,
+			MinLength:  3,
+			AllowEmpty: true,
+		}
+		scriptedPrompt(&p, "\r")
+
+		value, err := p.Run()
+		if err != nil {
+			t.Fatalf("Unexpected error %v", err)
+		}
+
+		if value != "" {
+			t.Errorf("Expected an empty value, got %q", value)
+		}
+	})
+
+	t.Run("when not allowed", func(t *testing.T) {
+		p := Prompt{Label: "Nickname"}
+		out := scriptedPrompt(&p, "\rgo\r")
+
+		value, err := p.Run()
+		if err != nil {
+			t.Fatalf("Unexpected error %v", err)
+		}
+
+		if value != "go" {
+			t.Errorf("Expected %q, got %q", "go", value)
+		}
+
+		exp := "a value is required"
+		if !strings.Contains(out.String(), exp) {
+			t.Errorf("Expected output to contain %q, got %q", exp, out.String())
+		}
+
+		first := strings.SplitN(out.String(), "\n", 2)[0]
+		if strings.Contains(first, "✗") {
+			t.Errorf("Expected the prompt to start valid, got %q", first)
+		}
+	})
+
+	t.Run("when not allowed with a default", func(t *testing.T) {
+		p := Prompt{
+			Label:     "Nickname",
+			Default:   "gopher",
+			AllowEdit: true,
+			Validate:  required,
+		}
+		out := scriptedPrompt(&p, strings.Repeat("\b", 6)+"\rgo\r")
+
+		value, err := p.Run()
+		if err != nil {
+			t.Fatalf("Unexpected error %v", err)
+		}
+
+		if value != "go" {
+			t.Errorf("Expected %q, got %q", "go", value)
+		}
+
+		if !strings.Contains(out.String(), "required") {
+			t.Errorf("Expected output to contain the validation error, got %q", out.String())
+		}
+	})
+}
+
+func TestPromptRunContext(t *testing.T) {
+	t.Run("when the context is done", func(t *testing.T) {
+		ctx, cancel := context.WithTimeout(context.Background(), 100*time.Millisecond)
+		defer cancel()
+
+		p := Prompt{Label: "Name"}
+		scriptedPrompt(&p, "go")
+
+		_, err := p.RunContext(ctx)
+		if err != context.DeadlineExceeded {
+			t.Errorf("Expected %v, got %v", context.DeadlineExceeded, err)
+		}
+	})
+
+	t.Run("cancels the validation", func(t *testing.T) {
+		ctx, cancel := context.WithCancel(context.Background())
+
+		validating := make(chan struct{})
+		var once sync.Once
+		p := Prompt{
+			Label: "Name",
+			ValidateContext: func(ctx context.Context, input string) error {
+				if input == "" {
+					return nil
+				}
+				once.Do(func() { close(validating) })
+				<-ctx.Done()
+				return ctx.Err()
+			},
+		}
+		scriptedPrompt(&p, "go")
+
+		go func() {
+			<-validating
+			cancel()
+		}()
+
+		_, err := p.RunContext(ctx)
+		if err != context.Canceled {
+			t.Errorf("Expected %v, got %v", context.Canceled, err)
+		}
+	})
+
+	t.Run("when the value is entered", func(t *testing.T) {
+		p := Prompt{
+			Label: "Name",
+			ValidateContext: func(ctx context.Context, input string) error {
+				return ctx.Err()
+			},
+		}
+		scriptedPrompt(&p, "go\r")
+
+		value, err := p.RunContext(context.Background())
+		if err != nil {
+			t.Fatalf("Unexpected error %v", err)
+		}
+
+		if value != "go" {
+			t.Errorf("Expected %q, got %q", "go", value)
+		}
+	})
+}
+
+func TestPromptResult(t *testing.T) {
+	p := Prompt{
+		Label: "Region",
+		Templates: &PromptTemplates{
+			Result: "✓ {{ .Label }}: {{ .Value }}",
+		},
+	}
+	out := scriptedPrompt(&p, "us-east-1\r")
+
+	_, err := p.Run()
+	if err != nil {
+		t.Fatalf("Unexpected error %v", err)
+	}
+
+	exp := "\x1b[2K\r✓ Region: us-east-1\n"
+	if !strings.HasSuffix(out.String(), exp+showCursor) {
+		t.Errorf("Expected output to end with %q, got %q", exp, out.String())
+	}
+}
+
+func TestPromptRunInput(t *testing.T) {
+	tcs := []struct {
+		scenario string
+		keys     string
+		expect   Input
+	}{
+		{scenario: "return a command", keys: ":quit\r", expect: Input{Value: "quit", Command: true}},
+		{scenario: "skip the validation of commands", keys: ":\r", expect: Input{Command: true}},
+		{scenario: "return the input", keys: "42\r", expect: Input{Value: "42"}},
+		{scenario: "validate the input", keys: "ab\r\b\b12\r", expect: Input{Value: "12"}},
+	}
+
+	for _, tc := range tcs {
+		t.Run(tc.scenario, func(t *testing.T) {
+			p := Prompt{
+				Label:         "Number",
+				CommandPrefix: ":",
+				Validate: func(input string) error {
+					for _, r := range input {
+						if !unicode.IsDigit(r) {
+							return errors.New("not a number")
+						}
+					}
+					return nil
+				},
+			}
+			scriptedPrompt(&p, tc.keys)
+
+			input, err := p.RunInput()
+			if err != nil {
+				t.Fatalf("Unexpected error %v", err)
+			}
+
+			if input != tc.expect {
+				t.Errorf("Expected %+v, got %+v", tc.expect, input)
+			}
+		})
+	}
+}
+
+func TestPromptOnSubmit(t *testing.T) {
+	// the suggestion is only entered once the user accepts it by pressing enter again
+	onSubmit := func(input string) (string, bool, error) {
+		if input == "bad" {
+			return "", false, errors.New("not allowed")
+		}
+		lower := strings.ToLower(input)
+		return lower, lower == input, nil
+	}
+
+	tcs := []struct {
+		scenario string
+		keys     string
+		expect   string
+	}{
+		{scenario: "enter the value as is", keys: "gopher\r", expect: "gopher"},
+		{scenario: "confirm the suggestion", keys: "Gopher\r\r", expect: "gopher"},
+		{scenario: "edit the suggestion", keys: "Gopher\r\bs\r", expect: "gophes"},
+		{scenario: "keep the input on error", keys: "bad\rge\r", expect: "badge"},
+	}
+
+	for _, tc := range tcs {
+		t.Run(tc.scenario, func(t *testing.T) {
+			p := Prompt{Label: "Name", OnSubmit: onSubmit}
+			scriptedPrompt(&p, tc.keys)
+
+			value, err := p.Run()
+			if err != nil {
+				t.Fatalf("Unexpected error %v", err)
+			}
+
+			if value != tc.expect {
+				t.Errorf("Expected %q, got %q", tc.expect, value)
+			}
+		})
+	}
+}
+
+func TestPromptAcceptedDefault(t *testing.T) {
+	cases := []struct {
+		name     string
+		prompt   Prompt
+		keys     string
+		accepted bool
+	}{
+		{name: "when accepted", prompt: Prompt{Default: "gopher"}, keys: "\r", accepted: true},
+		{name: "when moving the cursor", prompt: Prompt{Default: "gopher", AllowEdit: true}, keys: "\x1b[D\r", accepted: true},
+		{name: "when typed", prompt: Prompt{Default: "gopher"}, keys: "go\r", accepted: false},
+		{name: "when edited back", prompt: Prompt{Default: "gopher", AllowEdit: true}, keys: "s\b\r", accepted: false},
+		{name: "without default", prompt: Prompt{AllowEmpty: true}, keys: "\r", accepted: false},
+		{name: "when confirmed by default", prompt: Prompt{IsConfirm: true, Default: "y"}, keys: "\r", accepted: true},
+		{name: "with a confirm default", prompt: Prompt{IsConfirm: true, ConfirmDefault: true}, keys: "\r", accepted: true},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			p := tc.prompt
+			p.Label = "Name"
+			scriptedPrompt(&p, tc.keys)
+
+			_, err := p.Run()
+			if err != nil {
+				t.Fatalf("Unexpected error %v", err)
+			}
+
+			if p.AcceptedDefault() != tc.accepted {
+				t.Errorf("Expected AcceptedDefault to be %t", tc.accepted)
+			}
+		})
+	}
+}
+
+func TestPromptAutoSubmitAt(t *testing.T) {
+	t.Run("when typing the code", func(t *testing.T) {
+		p := Prompt{
+			Label:        "Code",
+			AutoSubmitAt: 6,
+		}
+		scriptedPrompt(&p, "123456")
+
+		value, err := p.Run()
+		if err != nil {
+			t.Fatalf("Unexpected error %v", err)
+		}
+
+		if value != "123456" {
+			t.Errorf("Expected %q, got %q", "123456", value)
+		}
+	})
+
+	t.Run("when the code is invalid", func(t *testing.T) {
+		p := Prompt{
+			Label:        "Code",
+			AutoSubmitAt: 6,
+			Validate: func(input string) error {
+				if input == "000000" {
+					return errors.New("invalid code")
+				}
+				return nil
+			},
+		}
+		scriptedPrompt(&p, "000000\b1")
+
+		value, err := p.Run()
+		if err != nil {
+			t.Fatalf("Unexpected error %v", err)
+		}
+
+		if value != "000001" {
+			t.Errorf("Expected %q, got %q", "000001", value)
+		}
+	})
+}
+
+func TestPromptMaxRetries(t *testing.T) {
+	validate := func(input string) error {
+		if input != "ok" {
+			return errors.New("invalid value")
+		}
+		return nil
+	}
+
+	t.Run("when the limit is reached", func(t *testing.T) {
+		p := Prompt{
+			Label:      "Value",
+			Validate:   validate,
+			MaxRetries: 2,
+		}
+		scriptedPrompt(&p, "a\rb\r")
+
+		_, err := p.Run()
+		if err != ErrMaxRetries {
+			t.Errorf("Expected ErrMaxRetries, got %v", err)
+		}
+	})
+
+	t.Run("when counting down the attempts", func(t *testing.T) {
+		p := Prompt{
+			Label:      "PIN",
+			Validate:   validate,
+			MaxRetries: 3,
+		}
+		out := scriptedPrompt(&p, "a\rb\rc\r")
+
+		_, err := p.Run()
+		if err != ErrMaxRetries {
+			t.Errorf("Expected ErrMaxRetries, got %v", err)
+		}
+
+		for _, exp := range []string{"(2 tries left)", "(1 try left)"} {
+			if !strings.Contains(out.String(), exp) {
+				t.Errorf("Expected output to contain %q, got %q", exp, out.String())
+			}
+		}
+	})
+
+	t.Run("with a custom template", func(t *testing.T) {
+		p := Prompt{
+			Label:      "PIN",
+			Validate:   validate,
+			MaxRetries: 3,
+			Templates: &PromptTemplates{
+				ValidationError: "{{ . }} (attempt {{ attempts }}, {{ attemptsLeft }} left)",
+			},
+		}
+		out := scriptedPrompt(&p, "a\rb\r\b\bok\r")
+
+		value, err := p.Run()
+		if err != nil {
+			t.Fatalf("Unexpected error %v", err)
+		}
+
+		if value != "ok" {
+			t.Errorf("Expected %q, got %q", "ok", value)
+		}
+
+		for _, exp := range []string{"invalid value (attempt 1, 2 left)", "invalid value (attempt 2, 1 left)"} {
+			if !strings.Contains(out.String(), exp) {
+				t.Errorf("Expected output to contain %q, got %q", exp, out.String())
+			}
+		}
+	})
+
+	t.Run("when a valid value is entered in time", func(t *testing.T) {
+		p := Prompt{
+			Label:      "Value",
+			Validate:   validate,
+			MaxRetries: 2,
+		}
+		scriptedPrompt(&p, "a\r\b\bok\r")
+
+		value, err := p.Run()
+		if err != nil {
+			t.Fatalf("Unexpected error %v", err)
+		}
+
+		if value != "ok" {
+			t.Errorf("Expected %q, got %q", "ok", value)
+		}
+	})
+}
+
+func TestPromptIcons(t *testing.T) {
+	p := Prompt{
+		Label: "Name",
+		Validate: func(input string) error {
+			if len(input) < 2 {
+				return errors.New("too short")
+			}
+			return nil
+		},
+		Templates: &PromptTemplates{
+			QuestionIcon: "?",
+			SuccessIcon:  "+",
+			ErrorIcon:    "-",
+		},
+	}
+	out := scriptedPrompt(&p, "go\r")
+
+	_, err := p.Run()
+	if err != nil {
+		t.Fatalf("Unexpected error %v", err)
+	}
+
+	bold := Styler(FGBold)
+	for _, icon := range []string{"-", "+"} {
+		if exp := bold(icon) + " " + bold("Name"); !strings.Contains(out.String(), exp) {
+			t.Errorf("Expected output to contain %q, got %q", exp, out.String())
+		}
+	}
+
+	for _, icon := range []string{IconGood, IconBad} {
+		if strings.Contains(out.String(), icon) {
+			t.Errorf("Expected output not to contain %q, got %q", icon, out.String())
+		}
+	}
+
+	p = Prompt{
+		Label:     "Continue",
+		IsConfirm: true,
+		Templates: &PromptTemplates{QuestionIcon: "?"},
+	}
+	out = scriptedPrompt(&p, "y\r")
+
+	_, err = p.Run()
+	if err != nil {
+		t.Fatalf("Unexpected error %v", err)
+	}
+
+	if exp := bold("?") + " " + bold("Continue"); !strings.Contains(out.String(), exp) {
+		t.Errorf("Expected output to contain %q, got %q", exp, out.String())
+	}
+}
+
+func TestPromptMultilineLabel(t *testing.T) {
+	p := Prompt{Label: "Pick a name for the project\nName"}
+	out := scriptedPrompt(&p, "ab\b\r")
+
+	value, err := p.Run()
+	if err != nil {
+		t.Fatalf("Unexpected error %v", err)
+	}
+
+	if value != "a" {
+		t.Errorf("Expected %q, got %q", "a", value)
+	}
+
+	// each redraw clears both lines of the label before displaying them again
+	clear := regexp.QuoteMeta(moveUp + clearLine + "\r")
+	for _, run := range regexp.MustCompile("(?:"+clear+")+").FindAllString(out.String(), -1) {
+		if n := strings.Count(run, moveUp); n != 2 {
+			t.Errorf("Expected each redraw to clear 2 lines, got %d in %q", n, out.String())
+		}
+	}
+
+	if exp := "Pick a name for the project\n" + clearLine + "\rName"; !strings.Contains(out.String(), exp) {
+		t.Errorf("Expected output to contain %q, got %q", exp, out.String())
+	}
+}
+
+func TestPromptHint(t *testing.T) {
+	p := Prompt{Label: "Region", Hint: "e.g. us-east-1"}
+	out := scriptedPrompt(&p, "eu\r")
+
+	state := &promptRun{}
+	_, err := p.run(context.Background(), state)
+	if err != nil {
+		t.Fatalf("Unexpected error %v", err)
+	}
+
+	hint := Styler(FGFaint)("e.g. us-east-1")
+	if !strings.Contains(out.String(), "\n"+clearLine+"\r"+hint+"\n") {
+		t.Errorf("Expected the hint on a line of its own below the input, got %q", out.String())
+	}
+
+	// each redraw clears the line of the hint along with the input
+	clear := regexp.QuoteMeta(moveUp + clearLine + "\r")
+	for _, run := range regexp.MustCompile("(?:"+clear+")+").FindAllString(out.String(), -1) {
+		if n := strings.Count(run, moveUp); n != 2 {
+			t.Errorf("Expected each redraw to clear 2 lines, got %d in %q", n, out.String())
+		}
+	}
+
+	result := out.String()[strings.LastIndex(out.String(), clearLine):]
+	if strings.Contains(result, "e.g.") {
+		t.Errorf("Expected the hint to be removed once entered, got %q", result)
+	}
+	if state.lines != 1 {
+		t.Errorf("Expected the entered prompt to take 1 line, got %d", state.lines)
+	}
+}
+
+func TestPromptTemplateData(t *testing.T) {
+	p := Prompt{
+		Label: "Password",
+		Templates: &PromptTemplates{
+			Valid:   `{{ . }} for {{ data "User" }}@{{ data "Env" }}: `,
+			Success: `{{ . }} for {{ data "User" }}: `,
+		},
+		TemplateData: map[string]interface{}{"User": "alice", "Env": "staging"},
+	}
+	out := scriptedPrompt(&p, "x\r")
+
+	_, err := p.Run()
+	if err != nil {
+		t.Fatalf("Unexpected error %v", err)
+	}
+
+	for _, exp := range []string{"Password for alice@staging: ", "Password for alice: x"} {
+		if !strings.Contains(out.String(), exp) {
+			t.Errorf("Expected output to contain %q, got %q", exp, out.String())
+		}
+	}
+}
+
+func TestPromptNoCursor(t *testing.T) {
+	p := Prompt{
+		Label:   "Name",
+		Pointer: NoCursor,
+	}
+	out := scriptedPrompt(&p, "abc\x1b[D\x1b[DX\b\bY\r")
+
+	value, err := p.Run()
+	if err != nil {
+		t.Fatalf("Unexpected error %v", err)
+	}
+
+	if value != "Ybc" {
+		t.Errorf("Expected %q, got %q", "Ybc", value)
+	}
+
+	if strings.Contains(out.String(), "█") {
+		t.Errorf("Expected output not to contain a cursor, got %q", out.String())
+	}
+
+	if !strings.HasPrefix(out.String(), hideCursor) || !strings.HasSuffix(out.String(), showCursor) {
+		t.Errorf("Expected the terminal cursor to be hidden while the prompt runs, got %q", out.String())
+	}
+}
+
+func TestPromptHistorySearch(t *testing.T) {
+	entries := []string{"ls", "cd /tmp", "git status", "cd /home"}
+
+	tcs := []struct {
+		scenario string
+		keys     string
+		value    string
+	}{
+		{scenario: "accepts the most recent match", keys: "\x12cd\r", value: "cd /home"},
+		{scenario: "cycles through older matches", keys: "\x12cd\x12\r", value: "cd /tmp"},
+		{scenario: "keeps the last match", keys: "\x12cd\x12\x12\r", value: "cd /tmp"},
+		{scenario: "narrows down the match", keys: "\x12cd /t\r", value: "cd /tmp"},
+		{scenario: "searches again after backspace", keys: "\x12cd /t\x7fh\r", value: "cd /home"},
+		{scenario: "cancels back to the input", keys: "abc\x12cd\x07d\r", value: "abcd"},
+		{scenario: "edits the match", keys: "\x12git\x05 -s\r", value: "git status -s"},
+		{scenario: "keeps the input without a match", keys: "ab\x12\r", value: "ab"},
+	}
+
+	for _, tc := range tcs {
+		t.Run(tc.scenario, func(t *testing.T) {
+			h := &History{}
+			for _, e := range entries {
+				h.Add(e)
+			}
+
+			p := Prompt{Label: "Command", History: h}
+			scriptedPrompt(&p, tc.keys)
+
+			value, err := p.Run()
+			if err != nil {
+				t.Fatalf("Unexpected error %v", err)
+			}
+
+			if value != tc.value {
+				t.Errorf("Expected %q, got %q", tc.value, value)
+			}
+
+			exp := append(append([]string(nil), entries...), tc.value)
+			if !reflect.DeepEqual(h.Entries(), exp) {
+				t.Errorf("Expected the history %q, got %q", exp, h.Entries())
+			}
+		})
+	}
+
+	t.Run("displays the search", func(t *testing.T) {
+		h := &History{}
+		for _, e := range entries {
+			h.Add(e)
+		}
+
+		p := Prompt{Label: "Command", History: h}
+		out := scriptedPrompt(&p, "\x12tmp\x12x\r")
+
+		if _, err := p.Run(); err != nil {
+			t.Fatalf("Unexpected error %v", err)
+		}
+
+		for _, exp := range []string{"(reverse-i-search)`tmp': cd /█mp", "(failed reverse-i-search)`tmp': cd /█mp", "(failed reverse-i-search)`tmpx': cd /tmp"} {
+			if !strings.Contains(out.String(), exp) {
+				t.Errorf("Expected output to contain %q, got %q", exp, out.String())
+			}
+		}
+	})
+
+	t.Run("ignores masked input", func(t *testing.T) {
+		h := &History{}
+		h.Add("secret")
+
+		p := Prompt{Label: "Password", Mask: '*', History: h}
+		scriptedPrompt(&p, "pass\r")
+
+		if _, err := p.Run(); err != nil {
+			t.Fatalf("Unexpected error %v", err)
+		}
+
+		if exp := []string{"secret"}; !reflect.DeepEqual(h.Entries(), exp) {
+			t.Errorf("Expected the history %q, got %q", exp, h.Entries())
+		}
+	})
+}
+
+func TestPromptRapidInput(t *testing.T) {
+	// the keys are all read at once, readline handling them on its own goroutine while the prompt validates and
+	// renders the entered lines, which is meant to be run with the race detector
+	p := Prompt{
+		Label: "Name",
+		Validate: func(input string) error {
+			if input != "done" {
+				return errors.New("not done")
+			}
+			return nil
+		},
+	}
+	scriptedPrompt(&p, strings.Repeat("ab\x7fc\x1b[Dd\x1b[C\r\x17", 50)+"done\r")
+
+	value, err := p.Run()
+	if err != nil {
+		t.Fatalf("Unexpected error %v", err)
+	}
+
+	if value != "done" {
+		t.Errorf("Expected %q, got %q", "done", value)
+	}
+}
+
+func TestPromptOnUnknownKey(t *testing.T) {
+	var keys []rune
+	p := Prompt{Label: "Name"}
+	p.OnUnknownKey = func(key rune) {
+		keys = append(keys, key)
+		p.Hint = "press ? for help"
+	}
+	out := scriptedPrompt(&p, "a\x0f\x7fb\x1b[Dc\r")
+
+	value, err := p.Run()
+	if err != nil {
+		t.Fatalf("Unexpected error %v", err)
+	}
+
+	if value != "cb" {
+		t.Errorf("Expected %q, got %q", "cb", value)
+	}
+
+	if !reflect.DeepEqual(keys, []rune{0x0f}) {
+		t.Errorf("Expected the unknown keys %q, got %q", []rune{0x0f}, keys)
+	}
+
+	if !strings.Contains(out.String(), "press ? for help") {
+		t.Errorf("Expected the hint set by OnUnknownKey, got %q", out.String())
+	}
+
+	p = Prompt{Label: "Name"}
+	scriptedPrompt(&p, "a\x0fb\r")
+
+	value, err = p.Run()
+	if err != nil {
+		t.Fatalf("Unexpected error %v", err)
+	}
+
+	if value != "ab" {
+		t.Errorf("Expected the unknown key to be ignored, got %q", value)
+	}
+}
+
+func TestPromptConfirmDefault(t *testing.T) {
+	cases := []struct {
+		name string
+		yes  bool
+		keys string
+		hint string
+		err  error
+	}{
+		{name: "answers yes by default", yes: true, keys: "\r", hint: "[Y/n]"},
+		{name: "answers no by default", yes: false, keys: "\r", hint: "[y/N]", err: ErrAbort},
+		{name: "answers no over a yes default", yes: true, keys: "n\r", hint: "[Y/n]", err: ErrAbort},
+		{name: "answers yes over a no default", yes: false, keys: "y\r", hint: "[y/N]"},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			p := Prompt{Label: "Continue", IsConfirm: true, ConfirmDefault: tc.yes}
+			out := scriptedPrompt(&p, tc.keys)
+
+			_, err := p.Run()
+			if err != tc.err {
+				t.Errorf("Expected error %v, got %v", tc.err, err)
+			}
+
+			if !strings.Contains(out.String(), tc.hint) {
+				t.Errorf("Expected the hint %q, got %q", tc.hint, out.String())
+			}
+		})
+	}
+}
@@ -22,6 +22,18 @@ var ErrInterrupt = errors.New("^C")
 // ErrAbort is the error returned when confirm prompts are supplied "n"
 var ErrAbort = errors.New("")
 
+// ErrMaxRetries is the error returned from prompts when the user entered an invalid value more times than
+// allowed by their MaxRetries.
+var ErrMaxRetries = errors.New("too many invalid attempts")
+
+// ErrTimeout is the error returned from prompts when the user didn't press any key for as long as their
+// IdleTimeout.
+var ErrTimeout = errors.New("idle timeout")
+
+// ErrReprompt is the error returned by the OnSelect function of a select to keep the select open instead of
+// returning the selected item.
+var ErrReprompt = errors.New("reprompt")
+
 // ValidateFunc is a placeholder type for any validation functions that validates a given input. It should return
 // a ValidationError if the input is not valid.
 type ValidateFunc func(string) error
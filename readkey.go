@@ -0,0 +1,120 @@
+package promptui
+
+import (
+	"io"
+	"strconv"
+	"strings"
+	"unicode/utf8"
+
+	"github.com/chzyer/readline"
+)
+
+// ReadKey waits for the user to press a single key and returns it right away, without waiting for enter. The
+// terminal is put in raw mode while waiting and restored before returning.
+//
+// Printable keys are returned as is. The arrows, the other special keys and the KeySequences are returned as
+// the same key codes used inside the prompts, which can be compared to the key variables like KeyPrev or
+// KeyEnter. It returns ErrInterrupt if the user pressed ctrl-c and ErrEOF if the user pressed ctrl-d or the
+// input was closed.
+func ReadKey() (rune, error) {
+	fd := readline.GetStdin()
+	if IsTerminal(fd) {
+		state, err := readline.MakeRaw(fd)
+		if err != nil {
+			return 0, err
+		}
+		defer readline.Restore(fd, state)
+	}
+
+	return readKey(readline.Stdin)
+}
+
+// readKey reads a single key from r. A single read is performed, which returns all the bytes sent by the
+// terminal for a key press when r is a terminal in raw mode.
+func readKey(r io.Reader) (rune, error) {
+	buf := make([]byte, 32)
+
+	n, err := r.Read(buf)
+	if n == 0 {
+		if err == nil || err == io.EOF {
+			err = ErrEOF
+		}
+		return 0, err
+	}
+
+	switch buf[0] {
+	case readline.CharInterrupt:
+		return 0, ErrInterrupt
+	case readline.CharDelete:
+		return 0, ErrEOF
+	}
+
+	if code, ok := KeySequences[string(buf[:n])]; ok {
+		return code, nil
+	}
+
+	return decodeKey(buf[:n]), nil
+}
+
+// decodeKey returns the first key encoded in b, translating the escape sequences sent by terminals for the
+// arrows and the other special keys into key codes.
+func decodeKey(b []byte) rune {
+	r, size := utf8.DecodeRune(b)
+	if r != readline.CharEsc || size == len(b) {
+		return r
+	}
+
+	seq := string(b[size:])
+
+	switch seq[0] {
+	case '[':
+		// CSI sequences are made of numeric parameters ended by a final letter, like "[3~" or "[1;5A".
+		end := strings.IndexFunc(seq[1:], func(c rune) bool {
+			return c != ';' && (c < '0' || c > '9')
+		})
+		if end == -1 {
+			return r
+		}
+
+		param := seq[1 : end+1]
+		switch seq[end+1] {
+		case 'A':
+			return readline.CharPrev
+		case 'B':
+			return readline.CharNext
+		case 'C':
+			return readline.CharForward
+		case 'D':
+			return readline.CharBackward
+		case 'H':
+			return readline.CharLineStart
+		case 'F':
+			return readline.CharLineEnd
+		case '~':
+			if n, err := strconv.Atoi(param); err == nil && n == 3 {
+				return readline.CharDelete
+			}
+		}
+	case 'O':
+		if len(seq) < 2 {
+			return r
+		}
+
+		switch seq[1] {
+		case 'A':
+			return readline.CharPrev
+		case 'B':
+			return readline.CharNext
+		case 'C':
+			return readline.CharForward
+		case 'D':
+			return readline.CharBackward
+		case 'H':
+			return readline.CharLineStart
+		case 'F':
+			return readline.CharLineEnd
+		}
+	}
+
+	return r
+}
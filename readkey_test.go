@@ -0,0 +1,50 @@
+package promptui
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/chzyer/readline"
+)
+
+func TestReadKey(t *testing.T) {
+	tcs := []struct {
+		scenario string
+		input    string
+		key      rune
+		err      error
+	}{
+		{scenario: "printable key", input: "y", key: 'y'},
+		{scenario: "multi byte key", input: "é", key: 'é'},
+		{scenario: "only the first key", input: "yes", key: 'y'},
+		{scenario: "enter", input: "\r", key: KeyEnter},
+		{scenario: "backspace", input: "\x7f", key: readline.CharBackspace},
+		{scenario: "up arrow", input: "\x1b[A", key: KeyPrev},
+		{scenario: "down arrow", input: "\x1b[B", key: KeyNext},
+		{scenario: "right arrow", input: "\x1b[C", key: KeyForward},
+		{scenario: "left arrow", input: "\x1b[D", key: KeyBackward},
+		{scenario: "arrow with modifiers", input: "\x1b[1;5A", key: KeyPrev},
+		{scenario: "application mode arrow", input: "\x1bOB", key: KeyNext},
+		{scenario: "home", input: "\x1b[H", key: readline.CharLineStart},
+		{scenario: "end", input: "\x1bOF", key: readline.CharLineEnd},
+		{scenario: "delete", input: "\x1b[3~", key: readline.CharDelete},
+		{scenario: "escape", input: "\x1b", key: readline.CharEsc},
+		{scenario: "unknown sequence", input: "\x1b[15~", key: readline.CharEsc},
+		{scenario: "ctrl-c", input: "\x03", err: ErrInterrupt},
+		{scenario: "ctrl-d", input: "\x04", err: ErrEOF},
+		{scenario: "closed input", input: "", err: ErrEOF},
+	}
+
+	for _, tc := range tcs {
+		t.Run(tc.scenario, func(t *testing.T) {
+			key, err := readKey(strings.NewReader(tc.input))
+			if err != tc.err {
+				t.Fatalf("expected error %v, got %v", tc.err, err)
+			}
+
+			if key != tc.key {
+				t.Errorf("expected key %q, got %q", tc.key, key)
+			}
+		})
+	}
+}
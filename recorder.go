@@ -0,0 +1,249 @@
+package promptui
+
+import (
+	"bufio"
+	"encoding/json"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"strings"
+	"sync"
+	"unicode"
+	"unicode/utf8"
+
+	"github.com/chzyer/readline"
+)
+
+// The types of the events of a recording.
+const (
+	// RecordedKeys is the type of the events holding the keys read from stdin, as read.
+	RecordedKeys = "keys"
+
+	// RecordedRedacted is the type of the events holding the keys read by a prompt hiding its input with
+	// Secret or a Mask, each typed character replaced with an asterisk.
+	RecordedRedacted = "redacted"
+
+	// RecordedFrame is the type of the events holding the output written to stdout, escape codes included.
+	RecordedFrame = "frame"
+)
+
+// RecordedEvent is an event of a recording. A recording is made of one event per line, each one a JSON object
+// such as
+//
+//	{"type":"keys","data":"\u001b[B"}
+//	{"type":"frame","data":"\u001b[2K\r\u001b[1m?\u001b[0m Pick a color:\n"}
+type RecordedEvent struct {
+	// Type is the type of the event, either RecordedKeys, RecordedRedacted or RecordedFrame.
+	Type string `json:"type"`
+
+	// Data are the keys or the output of the event.
+	Data string `json:"data"`
+}
+
+// Recorder records the keys read by prompts and selects, along with the frames they display, for example to
+// reproduce the rendering issues of a session. Replay plays the keys of a recording back.
+//
+// The characters typed in the prompts hiding their input with Secret or a Mask aren't recorded: they are
+// replaced with asterisks in RecordedRedacted events, which keep the other keys so that a replay moves on to
+// the next prompt. The frames are recorded as displayed, including the characters a prompt reveals.
+//
+// A Recorder is safe to share between several prompts and selects run in turn, which get recorded one after
+// the other.
+type Recorder struct {
+	mu  sync.Mutex
+	w   io.Writer
+	err error
+}
+
+// NewRecorder creates a Recorder writing its recording to the given writer.
+func NewRecorder(w io.Writer) *Recorder {
+	return &Recorder{w: w}
+}
+
+// Err returns the first error met while writing the recording, if any. The events following it aren't recorded.
+func (r *Recorder) Err() error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return r.err
+}
+
+func (r *Recorder) record(typ string, data []byte) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if r.err != nil || len(data) == 0 {
+		return
+	}
+
+	line, err := json.Marshal(RecordedEvent{Type: typ, Data: string(data)})
+	if err == nil {
+		_, err = r.w.Write(append(line, '\n'))
+	}
+	r.err = err
+}
+
+// stdin returns the given stdin recording the keys read from it, redacted if the typed characters are secret. A
+// nil recorder records nothing.
+func (r *Recorder) stdin(stdin io.ReadCloser, redacted bool) io.ReadCloser {
+	if r == nil {
+		return stdin
+	}
+	if stdin == nil {
+		return readline.NewCancelableStdin(&recordedStdin{stdin: readline.Stdin, r: r, redacted: redacted})
+	}
+	return &recordedStdin{stdin: stdin, r: r, redacted: redacted}
+}
+
+// stdout returns the given stdout recording the frames written to it. A nil recorder records nothing.
+func (r *Recorder) stdout(stdout io.WriteCloser) io.WriteCloser {
+	if r == nil {
+		return stdout
+	}
+	if stdout == nil {
+		stdout = readline.Stdout
+	}
+	return &recordedStdout{stdout: stdout, r: r}
+}
+
+type recordedStdin struct {
+	stdin    io.ReadCloser
+	r        *Recorder
+	redacted bool
+
+	// partial is the start of a character split between two reads, recorded along with the rest of it.
+	partial []byte
+}
+
+func (s *recordedStdin) Read(p []byte) (int, error) {
+	n, err := s.stdin.Read(p)
+
+	keys := append(s.partial, p[:n]...)
+	s.partial = nil
+	for i := len(keys) - 1; i >= 0 && i >= len(keys)-utf8.UTFMax; i-- {
+		if utf8.RuneStart(keys[i]) {
+			if !utf8.FullRune(keys[i:]) {
+				keys, s.partial = keys[:i], append([]byte(nil), keys[i:]...)
+			}
+			break
+		}
+	}
+
+	if s.redacted {
+		s.r.record(RecordedRedacted, redactKeys(keys))
+	} else {
+		s.r.record(RecordedKeys, keys)
+	}
+	return n, err
+}
+
+// redactKeys replaces the characters typed among the given keys with asterisks, keeping the control keys and
+// the escape sequences of the special keys.
+func redactKeys(keys []byte) []byte {
+	var out []byte
+
+	for i := 0; i < len(keys); {
+		r, size := utf8.DecodeRune(keys[i:])
+		switch {
+		case r == readline.CharEsc:
+			size = escapeLength(keys[i:])
+			out = append(out, keys[i:i+size]...)
+		case unicode.IsPrint(r):
+			out = append(out, '*')
+		default:
+			out = append(out, keys[i:i+size]...)
+		}
+		i += size
+	}
+
+	return out
+}
+
+// escapeLength returns the length of the escape sequence b starts with: a CSI or SS3 sequence up to its final
+// byte, or the escape key followed by another one for the meta keys.
+func escapeLength(b []byte) int {
+	if len(b) < 2 {
+		return len(b)
+	}
+	if b[1] != '[' && b[1] != 'O' {
+		return 2
+	}
+
+	i := 2
+	for i < len(b) && b[i] >= 0x20 && b[i] <= 0x3f {
+		i++
+	}
+	if i < len(b) && b[i] >= 0x40 && b[i] <= 0x7e {
+		i++
+	}
+	return i
+}
+
+func (s *recordedStdin) Close() error {
+	return s.stdin.Close()
+}
+
+type recordedStdout struct {
+	stdout io.WriteCloser
+	r      *Recorder
+}
+
+func (s *recordedStdout) Write(p []byte) (int, error) {
+	s.r.record(RecordedFrame, p)
+	return s.stdout.Write(p)
+}
+
+func (s *recordedStdout) Close() error {
+	return s.stdout.Close()
+}
+
+// debugFrames returns the given writer copying each write to the debug writer, ignoring its errors. A nil debug
+// writer gets no copy.
+func debugFrames(w io.Writer, debug io.Writer) io.Writer {
+	if debug == nil {
+		return w
+	}
+	return &debugWriter{w: w, debug: debug}
+}
+
+type debugWriter struct {
+	w     io.Writer
+	debug io.Writer
+}
+
+func (d *debugWriter) Write(p []byte) (int, error) {
+	n, err := d.w.Write(p)
+	d.debug.Write(p)
+	return n, err
+}
+
+// Replay returns a stdin reading the keys of the given recording, as written by a Recorder, to run prompts and
+// selects again as they were run during the recording. Once all the keys are read, it returns io.EOF. The
+// redacted keys are played back as recorded, typing asterisks in the prompts hiding their input.
+func Replay(recording io.Reader) (io.ReadCloser, error) {
+	var keys strings.Builder
+
+	scanner := bufio.NewScanner(recording)
+	scanner.Buffer(nil, 1024*1024)
+	for line := 1; scanner.Scan(); line++ {
+		if strings.TrimSpace(scanner.Text()) == "" {
+			continue
+		}
+
+		var event RecordedEvent
+		err := json.Unmarshal(scanner.Bytes(), &event)
+		if err != nil {
+			return nil, fmt.Errorf("invalid event on line %d: %v", line, err)
+		}
+
+		if event.Type == RecordedKeys || event.Type == RecordedRedacted {
+			keys.WriteString(event.Data)
+		}
+	}
+
+	err := scanner.Err()
+	if err != nil {
+		return nil, err
+	}
+
+	return ioutil.NopCloser(strings.NewReader(keys.String())), nil
+}
@@ -0,0 +1,223 @@
+package promptui
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"io"
+	"io/ioutil"
+	"strings"
+	"testing"
+)
+
+func TestRecorder(t *testing.T) {
+	var recording bytes.Buffer
+	rec := NewRecorder(&recording)
+
+	s := Select{Label: "Color", Items: []string{"red", "green", "blue"}, Recorder: rec}
+	scriptedSelect(&s, "j\r")
+	_, item, err := s.Run()
+	if err != nil {
+		t.Fatalf("Unexpected error %v", err)
+	}
+	if item != "green" {
+		t.Fatalf("Expected %q, got %q", "green", item)
+	}
+
+	p := Prompt{Label: "Name", Recorder: rec}
+	scriptedPrompt(&p, "gopher\r")
+	_, err = p.Run()
+	if err != nil {
+		t.Fatalf("Unexpected error %v", err)
+	}
+
+	if err := rec.Err(); err != nil {
+		t.Fatalf("Unexpected recording error %v", err)
+	}
+
+	var keys string
+	var frames int
+	for _, line := range strings.Split(strings.TrimSpace(recording.String()), "\n") {
+		var event RecordedEvent
+		if err := json.Unmarshal([]byte(line), &event); err != nil {
+			t.Fatalf("Unexpected invalid event %q: %v", line, err)
+		}
+
+		switch event.Type {
+		case RecordedKeys:
+			keys += event.Data
+		case RecordedFrame:
+			frames++
+		default:
+			t.Errorf("Unexpected event type %q", event.Type)
+		}
+	}
+
+	if keys != "j\rgopher\r" {
+		t.Errorf("Expected the keys %q to be recorded, got %q", "j\rgopher\r", keys)
+	}
+
+	if frames == 0 || !strings.Contains(recording.String(), "gopher") {
+		t.Errorf("Expected the frames to be recorded, got %q", recording.String())
+	}
+
+	stdin, err := Replay(&recording)
+	if err != nil {
+		t.Fatalf("Unexpected error %v", err)
+	}
+
+	replayed, _ := ioutil.ReadAll(stdin)
+	if string(replayed) != "j\rgopher\r" {
+		t.Errorf("Expected the recorded keys to be replayed, got %q", replayed)
+	}
+}
+
+// frameWriter collects the frames written to it, failing every write.
+type frameWriter struct {
+	frames []string
+}
+
+func (w *frameWriter) Write(p []byte) (int, error) {
+	w.frames = append(w.frames, string(p))
+	return 0, errors.New("disk full")
+}
+
+func TestDebugWriter(t *testing.T) {
+	t.Run("when running a select", func(t *testing.T) {
+		debug := &frameWriter{}
+
+		s := Select{Label: "Color", Items: []string{"red", "green", "blue"}, DebugWriter: debug}
+		out := scriptedSelect(&s, "j\r")
+		_, item, err := s.Run()
+		if err != nil {
+			t.Fatalf("Unexpected error %v", err)
+		}
+		if item != "green" {
+			t.Fatalf("Expected %q, got %q", "green", item)
+		}
+
+		// the first frame, after the key press, and the selected item
+		if len(debug.frames) != 3 {
+			t.Fatalf("Expected 3 frames, got %q", debug.frames)
+		}
+		for _, frame := range debug.frames {
+			if !strings.Contains(out.String(), frame) {
+				t.Errorf("Expected the frame %q to be displayed, got %q", frame, out.String())
+			}
+		}
+		if !strings.Contains(debug.frames[2], "green") {
+			t.Errorf("Expected the last frame to display the selected item, got %q", debug.frames[2])
+		}
+	})
+
+	t.Run("when running a prompt", func(t *testing.T) {
+		debug := &frameWriter{}
+
+		p := Prompt{Label: "Name", DebugWriter: debug}
+		out := scriptedPrompt(&p, "go\r")
+		value, err := p.Run()
+		if err != nil {
+			t.Fatalf("Unexpected error %v", err)
+		}
+		if value != "go" {
+			t.Fatalf("Expected %q, got %q", "go", value)
+		}
+
+		if len(debug.frames) < 3 {
+			t.Fatalf("Expected a frame per key, got %q", debug.frames)
+		}
+		for _, frame := range debug.frames {
+			if !strings.Contains(out.String(), frame) {
+				t.Errorf("Expected the frame %q to be displayed, got %q", frame, out.String())
+			}
+		}
+	})
+
+	t.Run("when answering a prompt", func(t *testing.T) {
+		debug := &frameWriter{}
+
+		p := Prompt{Label: "Name", Name: "name", Answers: Answers{"name": "go"}, DebugWriter: debug}
+		scriptedPrompt(&p, "")
+		_, err := p.Run()
+		if err != nil {
+			t.Fatalf("Unexpected error %v", err)
+		}
+
+		if len(debug.frames) != 1 || !strings.Contains(debug.frames[0], "go") {
+			t.Errorf("Expected the answer to be displayed in a frame, got %q", debug.frames)
+		}
+	})
+}
+
+func TestRecorderSplitCharacter(t *testing.T) {
+	var recording bytes.Buffer
+	rec := NewRecorder(&recording)
+
+	stdin := rec.stdin(ioutil.NopCloser(io.MultiReader(strings.NewReader("a\xc3"), strings.NewReader("\xa9b"))), false)
+	ioutil.ReadAll(stdin)
+
+	replayed, err := Replay(&recording)
+	if err != nil {
+		t.Fatalf("Unexpected error %v", err)
+	}
+
+	keys, _ := ioutil.ReadAll(replayed)
+	if string(keys) != "aéb" {
+		t.Errorf("Expected %q, got %q", "aéb", keys)
+	}
+}
+
+func TestRecorderRedacted(t *testing.T) {
+	var recording bytes.Buffer
+	rec := NewRecorder(&recording)
+
+	p := Prompt{Label: "Password", Mask: '*', Recorder: rec}
+	scriptedPrompt(&p, "s3cr\x1b[Dé\x7f\r")
+	value, err := p.Run()
+	if err != nil {
+		t.Fatalf("Unexpected error %v", err)
+	}
+	if value != "s3cr" {
+		t.Fatalf("Expected %q, got %q", "s3cr", value)
+	}
+
+	if strings.Contains(recording.String(), "s3cr") {
+		t.Errorf("Expected the typed characters not to be recorded, got %q", recording.String())
+	}
+
+	var keys string
+	for _, line := range strings.Split(strings.TrimSpace(recording.String()), "\n") {
+		var event RecordedEvent
+		if err := json.Unmarshal([]byte(line), &event); err != nil {
+			t.Fatalf("Unexpected invalid event %q: %v", line, err)
+		}
+
+		switch event.Type {
+		case RecordedRedacted:
+			keys += event.Data
+		case RecordedKeys:
+			t.Errorf("Unexpected keys %q", event.Data)
+		}
+	}
+
+	if keys != "****\x1b[D*\x7f\r" {
+		t.Errorf("Expected the keys %q to be recorded, got %q", "****\x1b[D*\x7f\r", keys)
+	}
+
+	stdin, err := Replay(&recording)
+	if err != nil {
+		t.Fatalf("Unexpected error %v", err)
+	}
+
+	replayed, _ := ioutil.ReadAll(stdin)
+	if string(replayed) != keys {
+		t.Errorf("Expected the redacted keys to be replayed, got %q", replayed)
+	}
+}
+
+func TestReplayInvalid(t *testing.T) {
+	_, err := Replay(strings.NewReader("{\"type\":\"keys\",\"data\":\"a\"}\nnot json\n"))
+	if err == nil || !strings.Contains(err.Error(), "line 2") {
+		t.Errorf("Expected an error for line 2, got %v", err)
+	}
+}
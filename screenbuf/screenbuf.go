@@ -14,6 +14,20 @@ var (
 	moveDown  = []byte(esc + "1B")
 )
 
+// RedrawMode sets how a ScreenBuf redraws its lines once it is reset.
+type RedrawMode int
+
+const (
+	// Full clears all the previous lines before writing the new ones. It is the
+	// default mode.
+	Full RedrawMode = iota
+
+	// Incremental only rewrites the lines which changed since the previous
+	// flush, moving over the unchanged ones. It reduces the flicker and the
+	// amount of output sent to slow terminals, like the ones reached over ssh.
+	Incremental
+)
+
 // ScreenBuf is a convenient way to write to terminal screens. It creates,
 // clears and, moves up or down lines as needed to write the output to the
 // terminal using ANSI escape codes.
@@ -23,6 +37,14 @@ type ScreenBuf struct {
 	reset  bool
 	cursor int
 	height int
+	mode   RedrawMode
+
+	// lines displayed on the screen, used to skip the unchanged ones in
+	// incremental mode
+	lines [][]byte
+
+	// escape sequences written by the next flush after the lines
+	seqs []byte
 }
 
 // New creates and initializes a new ScreenBuf.
@@ -30,6 +52,11 @@ func New(w io.Writer) *ScreenBuf {
 	return &ScreenBuf{buf: &bytes.Buffer{}, w: w}
 }
 
+// SetMode sets how the ScreenBuf redraws its lines once reset.
+func (s *ScreenBuf) SetMode(mode RedrawMode) {
+	s.mode = mode
+}
+
 // Reset truncates the underlining buffer and marks all its previous lines to be
 // cleared during the next Write.
 func (s *ScreenBuf) Reset() {
@@ -52,9 +79,34 @@ func (s *ScreenBuf) Clear() error {
 	s.cursor = 0
 	s.height = 0
 	s.reset = false
+	s.lines = nil
+	return nil
+}
+
+// top moves back to the first line without clearing the previous ones, so that
+// they can be overwritten in place.
+func (s *ScreenBuf) top() error {
+	for i := 0; i < s.height; i++ {
+		_, err := s.buf.Write(moveUp)
+		if err != nil {
+			return err
+		}
+	}
+	s.cursor = 0
+	s.reset = false
 	return nil
 }
 
+// keep records b as the content of the line at the cursor.
+func (s *ScreenBuf) keep(b []byte) {
+	line := append([]byte(nil), b...)
+	if s.cursor < len(s.lines) {
+		s.lines[s.cursor] = line
+	} else {
+		s.lines = append(s.lines, line)
+	}
+}
+
 // Write writes a single line to the underlining buffer. If the ScreenBuf was
 // previously reset, all previous lines are cleared and the output starts from
 // the top. Lines with \r or \n will cause an error since they can interfere with the
@@ -65,10 +117,24 @@ func (s *ScreenBuf) Write(b []byte) (int, error) {
 	}
 
 	if s.reset {
-		if err := s.Clear(); err != nil {
+		reset := s.Clear
+		if s.mode == Incremental {
+			reset = s.top
+		}
+		if err := reset(); err != nil {
+			return 0, err
+		}
+	}
+
+	if s.mode == Incremental && s.cursor < s.height && s.cursor < len(s.lines) && bytes.Equal(s.lines[s.cursor], b) {
+		_, err := s.buf.Write(moveDown)
+		if err != nil {
 			return 0, err
 		}
+		s.cursor++
+		return len(b), nil
 	}
+	s.keep(b)
 
 	switch {
 	case s.cursor == s.height:
@@ -110,6 +176,14 @@ func (s *ScreenBuf) Write(b []byte) (int, error) {
 	}
 }
 
+// WriteSequence queues an escape sequence which doesn't move the cursor, such
+// as the ones showing or hiding it, to be written by the next Flush after the
+// lines. This sends it along with the lines in a single write, instead of in a
+// write of its own. Unlike the lines, queued sequences are kept by Reset.
+func (s *ScreenBuf) WriteSequence(b []byte) {
+	s.seqs = append(s.seqs, b...)
+}
+
 // Flush writes any buffered data to the underlying io.Writer, ensuring that any pending data is displayed.
 func (s *ScreenBuf) Flush() error {
 	for i := s.cursor; i < s.height; i++ {
@@ -123,9 +197,33 @@ func (s *ScreenBuf) Flush() error {
 		if err != nil {
 			return err
 		}
+		if i < len(s.lines) {
+			s.lines[i] = nil
+		}
+	}
+
+	if s.mode == Incremental {
+		// move back up to the end of the new lines, leaving the cleared ones below
+		for i := s.cursor; i < s.height; i++ {
+			_, err := s.buf.Write(moveUp)
+			if err != nil {
+				return err
+			}
+		}
+		s.height = s.cursor
+		if len(s.lines) > s.height {
+			s.lines = s.lines[:s.height]
+		}
 	}
 
-	_, err := s.buf.WriteTo(s.w)
+	_, err := s.buf.Write(s.seqs)
+	if err != nil {
+		return err
+	}
+	s.seqs = s.seqs[:0]
+
+	// the whole frame is sent in a single write to avoid flickering
+	_, err = s.buf.WriteTo(s.w)
 	if err != nil {
 		return err
 	}
@@ -144,6 +242,11 @@ func (s *ScreenBuf) Flush() error {
 	return nil
 }
 
+// Height returns the number of lines currently displayed by the ScreenBuf.
+func (s *ScreenBuf) Height() int {
+	return s.height
+}
+
 // WriteString is a convenient function to write a new line passing a string.
 // Check ScreenBuf.Write() for a detailed explanation of the function behaviour.
 func (s *ScreenBuf) WriteString(str string) (int, error) {
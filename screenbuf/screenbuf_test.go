@@ -132,3 +132,119 @@ func TestScreen(t *testing.T) {
 		})
 	}
 }
+
+func TestScreenIncremental(t *testing.T) {
+	// overwrite regular movement codes for easier visualization
+	clearLine = []byte("\\c")
+	moveUp = []byte("\\u")
+	moveDown = []byte("\\d")
+
+	var buf bytes.Buffer
+	s := New(&buf)
+	s.SetMode(Incremental)
+
+	tcs := []struct {
+		scenario string
+		lines    []string
+		expect   string
+		height   int
+	}{
+		{
+			scenario: "initial write",
+			lines:    []string{"line one", "line two"},
+			expect:   "\\cline one\n\\cline two\n",
+			height:   2,
+		},
+		{
+			scenario: "write of a changed line",
+			lines:    []string{"line one", "line 2"},
+			expect:   "\\u\\u\\d\\cline 2\\d",
+			height:   2,
+		},
+		{
+			scenario: "write of fewer lines",
+			lines:    []string{"line one"},
+			expect:   "\\u\\u\\d\\c\\d\\u",
+			height:   1,
+		},
+		{
+			scenario: "write of more lines",
+			lines:    []string{"line one", "line two", "line three"},
+			expect:   "\\u\\d\\cline two\n\\cline three\n",
+			height:   3,
+		},
+		{
+			scenario: "write of the same lines",
+			lines:    []string{"line one", "line two", "line three"},
+			expect:   "\\u\\u\\u\\d\\d\\d",
+			height:   3,
+		},
+	}
+
+	for _, tc := range tcs {
+		t.Run(tc.scenario, func(t *testing.T) {
+			buf.Reset()
+			s.Reset()
+
+			for _, line := range tc.lines {
+				_, err := s.WriteString(line)
+				if err != nil {
+					t.Fatalf("expected no error, got %v", err)
+				}
+			}
+
+			err := s.Flush()
+			if err != nil {
+				t.Fatalf("expected no error, got %v", err)
+			}
+
+			got := buf.String()
+
+			if tc.expect != got {
+				t.Errorf("expected %q, got %q", tc.expect, got)
+			}
+
+			if tc.height != s.Height() {
+				t.Errorf("expected height %d, got %d", tc.height, s.Height())
+			}
+		})
+	}
+}
+
+func TestScreenWriteSequence(t *testing.T) {
+	// overwrite regular movement codes for easier visualization
+	clearLine = []byte("\\c")
+	moveUp = []byte("\\u")
+	moveDown = []byte("\\d")
+
+	w := &countingWriter{}
+	s := New(w)
+
+	s.WriteSequence([]byte("\\s"))
+	s.Reset()
+	s.WriteString("line one")
+	s.WriteString("line two")
+
+	err := s.Flush()
+	if err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+
+	if expect := "\\cline one\n\\cline two\n\\s"; w.String() != expect {
+		t.Errorf("expected %q, got %q", expect, w.String())
+	}
+
+	if w.writes != 1 {
+		t.Errorf("expected a single write, got %d", w.writes)
+	}
+}
+
+type countingWriter struct {
+	bytes.Buffer
+	writes int
+}
+
+func (w *countingWriter) Write(b []byte) (int, error) {
+	w.writes++
+	return w.Buffer.Write(b)
+}
@@ -0,0 +1,88 @@
+package promptui
+
+import (
+	"fmt"
+	"reflect"
+	"strings"
+	"unicode"
+
+	"github.com/manifoldco/promptui/list"
+)
+
+// NewFieldSearcher creates a Searcher matching the searched term against the named fields of the given items,
+// which should be the same slice that is given to the select as Items. The term is matched, ignoring case, against
+// the values of all the fields joined together by spaces. See FoldString for how case is ignored.
+//
+// Fields are accessed like with the Field function, so missing fields are ignored, and fields that are not strings
+// are converted using fmt.Sprint. The values are read once when the searcher is created.
+func NewFieldSearcher(items interface{}, fields ...string) list.Searcher {
+	var values []string
+
+	slice := reflect.ValueOf(items)
+	if slice.Kind() == reflect.Slice {
+		values = make([]string, slice.Len())
+		for i := range values {
+			item := slice.Index(i).Interface()
+
+			parts := make([]string, len(fields))
+			for j, field := range fields {
+				parts[j] = fmt.Sprint(Field(item, field))
+			}
+			values[i] = FoldString(strings.Join(parts, " "))
+		}
+	}
+
+	return func(input string, index int) bool {
+		if index < 0 || index >= len(values) {
+			return false
+		}
+		return strings.Contains(values[index], FoldString(input))
+	}
+}
+
+// FoldString returns a case folded version of s, so that two strings that only differ by case are equal once
+// folded. It is used by the searcher helpers to ignore case and can be used to write custom searchers.
+//
+// Folding follows the unicode case folding rules, which are more thorough than strings.ToLower. For example,
+// the greek final sigma "ς" folds like "σ" and "Σ", and the german "ß" folds like "ss". Since searches have no
+// notion of language, the turkish dotted "İ" and dotless "ı" fold like "i" and "I", so that a term matches
+// regardless of the keyboard layout it was typed with.
+//
+// The folded string is only meant to be compared with other folded strings, not to be displayed.
+func FoldString(s string) string {
+	var b strings.Builder
+	b.Grow(len(s))
+
+	for _, r := range s {
+		switch r {
+		case 'ß', 'ẞ':
+			b.WriteString("ss")
+		case 'İ', 'ı':
+			b.WriteRune('i')
+		default:
+			b.WriteRune(foldRune(r))
+		}
+	}
+
+	return b.String()
+}
+
+// foldRune returns the lowest rune that is equivalent to r under simple case folding, or the lowercase
+// rune for the ascii letters.
+func foldRune(r rune) rune {
+	if r < unicode.MaxASCII {
+		return unicode.ToLower(r)
+	}
+
+	min := r
+	for f := unicode.SimpleFold(r); f != r; f = unicode.SimpleFold(f) {
+		if f < min {
+			min = f
+		}
+	}
+
+	if min < unicode.MaxASCII {
+		return unicode.ToLower(min)
+	}
+	return min
+}
@@ -0,0 +1,96 @@
+package promptui
+
+import "testing"
+
+func TestFieldSearcher(t *testing.T) {
+	type server struct {
+		Name        string
+		Description string
+		Tags        []string
+		Port        int
+	}
+
+	servers := []server{
+		{Name: "web", Description: "Public Website", Tags: []string{"frontend"}, Port: 443},
+		{Name: "db", Description: "Primary database", Tags: []string{"storage", "backup"}, Port: 5432},
+	}
+
+	tcs := []struct {
+		scenario string
+		fields   []string
+		input    string
+		expect   []bool
+	}{
+		{scenario: "match a single field", fields: []string{"Name"}, input: "we", expect: []bool{true, false}},
+		{scenario: "match any of the fields", fields: []string{"Name", "Description"}, input: "data", expect: []bool{false, true}},
+		{scenario: "match across fields", fields: []string{"Name", "Description"}, input: "web public", expect: []bool{true, false}},
+		{scenario: "ignore case", fields: []string{"Description"}, input: "WEBSITE", expect: []bool{true, false}},
+		{scenario: "match slice fields", fields: []string{"Tags"}, input: "backup", expect: []bool{false, true}},
+		{scenario: "match int fields", fields: []string{"Port"}, input: "443", expect: []bool{true, false}},
+		{scenario: "ignore missing fields", fields: []string{"Owner", "Name"}, input: "db", expect: []bool{false, true}},
+	}
+
+	for _, tc := range tcs {
+		t.Run(tc.scenario, func(t *testing.T) {
+			searcher := NewFieldSearcher(servers, tc.fields...)
+			for i, expect := range tc.expect {
+				if got := searcher(tc.input, i); got != expect {
+					t.Errorf("expected %q to match item %d: %t, got %t", tc.input, i, expect, got)
+				}
+			}
+		})
+	}
+
+	t.Run("when index is out of range", func(t *testing.T) {
+		searcher := NewFieldSearcher(servers, "Name")
+		if searcher("web", len(servers)) {
+			t.Errorf("expected no match")
+		}
+	})
+
+	t.Run("when items is not a slice", func(t *testing.T) {
+		searcher := NewFieldSearcher("web", "Name")
+		if searcher("web", 0) {
+			t.Errorf("expected no match")
+		}
+	})
+}
+
+func TestFoldString(t *testing.T) {
+	tcs := []struct {
+		scenario string
+		a        string
+		b        string
+	}{
+		{scenario: "ascii", a: "Hello World", b: "hELLO wORLD"},
+		{scenario: "accented latin", a: "ÉCOLE", b: "école"},
+		{scenario: "german sharp s", a: "Straße", b: "STRASSE"},
+		{scenario: "german capital sharp s", a: "GROẞ", b: "groß"},
+		{scenario: "turkish dotted capital i", a: "İstanbul", b: "istanbul"},
+		{scenario: "turkish dotless i", a: "ılık", b: "ILIK"},
+		{scenario: "greek final sigma", a: "ΟΔΥΣΣΕΥΣ", b: "οδυσσευς"},
+		{scenario: "long s", a: "ſtar", b: "STAR"},
+		{scenario: "kelvin sign", a: "K", b: "k"},
+		{scenario: "cyrillic", a: "МОСКВА", b: "москва"},
+	}
+
+	for _, tc := range tcs {
+		t.Run(tc.scenario, func(t *testing.T) {
+			a, b := FoldString(tc.a), FoldString(tc.b)
+			if a != b {
+				t.Errorf("expected %q and %q to fold the same, got %q and %q", tc.a, tc.b, a, b)
+			}
+		})
+	}
+
+	t.Run("field searcher", func(t *testing.T) {
+		cities := []struct{ Name string }{{Name: "İzmir"}, {Name: "Düsseldorf"}, {Name: "Gießen"}}
+		searcher := NewFieldSearcher(cities, "Name")
+
+		for i, input := range []string{"izmir", "DÜSSEL", "giessen"} {
+			if !searcher(input, i) {
+				t.Errorf("expected %q to match %q", input, cities[i].Name)
+			}
+		}
+	})
+}
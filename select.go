@@ -4,7 +4,11 @@ import (
 	"bytes"
 	"fmt"
 	"io"
+	"io/ioutil"
 	"os"
+	"reflect"
+	"strings"
+	"sync"
 	"text/template"
 
 	"github.com/chzyer/readline"
@@ -18,6 +22,28 @@ import (
 // SelectWithAdd's logic.
 const SelectedAdd = -1
 
+// SelectedNone is the index returned by Select when AllowNone is set and the user chose none of the items.
+const SelectedNone = -1
+
+// SelectLayout sets how a select displays its items.
+type SelectLayout int
+
+const (
+	// LayoutCompact displays each item on a single line, with the Details of the active item below the list. It
+	// is the default.
+	LayoutCompact SelectLayout = iota
+
+	// LayoutDetailed displays the Details of each item below it, inside the list.
+	LayoutDetailed
+)
+
+// keyReload is the key code pushed by a select when new items arrive from its Updates.
+const keyReload rune = 28
+
+// keyConfirm is the key code pushed by a select to confirm the selection, which readline receives as an enter
+// whatever the Confirm key of the select is. It lies in the private use area of Unicode, which no key types.
+const keyConfirm rune = 0xE000
+
 // Select represents a list of items used to enable selections, they can be used as search engines, menus
 // or as a list of items in a cli based prompt.
 type Select struct {
@@ -25,22 +51,49 @@ type Select struct {
 	// appended automatically to the label so it does not need to be added.
 	//
 	// The value for Label can be a simple string or a struct that will need to be accessed by dot notation
-	// inside the templates. For example, `{{ .Name }}` will display the name property of a struct.
+	// inside the templates. For example, `{{ .Name }}` will display the name property of a struct. Maps and any
+	// other values are also supported, the default template displays them using the fmt package. The label
+	// template also has access to the number of items, see the SelectTemplates docs for more info.
 	Label interface{}
 
 	// Items are the items to display inside the list. It expect a slice of any kind of values, including strings.
 	//
 	// If using a slice of strings, promptui will use those strings directly into its base templates or the
 	// provided templates. If using any other type in the slice, it will attempt to transform it into a string
-	// before giving it to its templates. Custom templates will override this behavior if using the dot notation
-	// inside the templates.
+	// before giving it to its templates, using the String method of the items implementing fmt.Stringer and the
+	// default formatting of the fmt package for the others, like numbers. Custom templates will override this
+	// behavior if using the dot notation inside the templates.
 	//
 	// For example, `{{ .Name }}` will display the name property of a struct.
+	//
+	// Items can also be a slice of interface{} holding values of different types. The dot notation only works
+	// in that case if all the items have the accessed fields, otherwise the field function can be used instead.
+	// For example, `{{ field . "Name" }}` will display the name property of the items having one and nothing for
+	// the others. The Searcher and the returned index always refer to the position of the item inside Items.
 	Items interface{}
 
+	// Updates is an optional channel of new items replacing the displayed ones while the select runs, for
+	// example to follow a list of running jobs. The list is displayed again as soon as new items arrive, with
+	// the current search applied to them. The highlighted item stays highlighted if it is found again among the
+	// new items, compared with reflect.DeepEqual, otherwise the cursor stays at the same position. Closing the
+	// channel stops the updates. Once Run returns, Items holds the last items received.
+	Updates <-chan []interface{}
+
 	// Size is the number of items that should appear on the select before scrolling is necessary. Defaults to 5.
 	Size int
 
+	// AutoSize sets whether to display as many items as fit in the terminal, below the help and the label and
+	// above the details of the first item, with at least one item. The size is computed each time the select
+	// runs, and Size is used instead when the height of the terminal can't be determined.
+	AutoSize bool
+
+	// Layout sets whether to display the Details of every item inside the list, or only the ones of the active
+	// item below it. The user switches between the layouts with the Layout key, and Layout holds the current
+	// one. In the detailed layout, the list shows as many items as fit in the lines it takes in the compact
+	// layout, at least one. The templates can tell the layouts apart with the detailed function. It has no
+	// effect without a Details template.
+	Layout SelectLayout
+
 	// CursorPos is the initial position of the cursor.
 	CursorPos int
 
@@ -54,12 +107,35 @@ type Select struct {
 	// HideSelected sets whether to hide the text displayed after an item is successfully selected.
 	HideSelected bool
 
+	// CopyToClipboard sets whether to copy the value of the selected item to the system clipboard. If it can't
+	// be copied, Run still returns the selected item, along with a ClipboardError.
+	CopyToClipboard bool
+
+	// TagFunc is an optional function returning the tags of the item at the given index of the Items, such as
+	// its categories. The Tag key then cycles through the tags of all the items, in the order they first
+	// appear, narrowing the list to the items having the current tag, then back to all the items. The tag
+	// filter applies along with the search, and the current tag is displayed in the header.
+	TagFunc func(index int) []string
+
+	// AnnotationFunc is an optional function returning a short annotation for the item at the given index of
+	// the Items, such as "[default]" or "(deprecated)", or an empty string for none. The annotations are
+	// displayed after the items, right-aligned in a column past the widest of the displayed items, and styled
+	// by the Annotation template.
+	AnnotationFunc func(index int) string
+
 	// Templates can be used to customize the select output. If nil is passed, the
 	// default templates are used. See the SelectTemplates docs for more info.
 	Templates *SelectTemplates
 
+	// TemplateData holds values of the application that the templates can display next to the items, such as
+	// the current user or environment. The templates read them with the data function, for example
+	// 	{{ data "User" }}
+	// Being a function, it never clashes with the fields of the items, which templates still reach through the
+	// dot, and it takes precedence over a function of the same name in the FuncMap.
+	TemplateData map[string]interface{}
+
 	// Keys is the set of keys used in select mode to control the command line interface. See the SelectKeys docs for
-	// more info.
+	// more info. Defaults to a copy of DefaultSelectKeys.
 	Keys *SelectKeys
 
 	// Searcher is a function that can be implemented to refine the base searching algorithm in selects.
@@ -69,12 +145,107 @@ type Select struct {
 	// it is implemented.
 	Searcher list.Searcher
 
+	// ScoredSearcher is a richer alternative to the Searcher, used instead of it when set. On top of whether an
+	// item fits the query, it tells how well it does and which parts of the item match. The Active and Inactive
+	// templates can emphasize those parts with the highlight function, given the text the ranges refer to, for
+	// example {{ highlight .Name }}.
+	ScoredSearcher list.ScoredSearcher
+
+	// SortByScore sets whether to list the items found by the ScoredSearcher from the best score to the worst, like
+	// fzf does, instead of in the order of Items. Items of equal score keep their order. The best match is then
+	// highlighted each time the query changes. It has no effect without a ScoredSearcher.
+	SortByScore bool
+
+	// KeepSearchSpaces sets whether to pass the search query to the Searcher as typed. By default, the whitespace
+	// around the query is trimmed, for example when pasting it.
+	KeepSearchSpaces bool
+
 	// StartInSearchMode sets whether or not the select mode should start in search mode or selection mode.
 	// For search mode to work, the Search property must be implemented.
 	StartInSearchMode bool
 
+	// AutoSelectSingle sets whether the select returns right away, without waiting for the user, when Items
+	// contains a single item.
+	AutoSelectSingle bool
+
+	// AutoSelectSearch sets whether the select returns as soon as a search narrows the list down to a single
+	// item, without waiting for the user to press enter.
+	AutoSelectSearch bool
+
+	// AllowNone lets the user choose none of the items by pressing the None key. In that case, Run returns the
+	// SelectedNone index with an empty value and no error. This differs from an interrupt (ctrl-c), which
+	// returns the ErrInterrupt error and should usually abort the whole program.
+	AllowNone bool
+
+	// NoneLabel is the text displayed after the user chose none of the items. Defaults to "None".
+	NoneLabel string
+
+	// OnSelect is an optional function called with the index of the item the user selected, before Run returns.
+	// The list is cleared while it runs, which allows it to run other prompts, for example to edit the selected
+	// item. If it returns ErrReprompt, the list is displayed again with the items reloaded, to show any edit made
+	// by the function, and the user can select another item. An error created with Reprompt does the same while
+	// displaying the error below the list until the user presses a key. Any other error is returned by Run.
+	OnSelect func(index int) error
+
+	// OnDelete is an optional function deleting the item at the given index of the Items when the user presses
+	// the Delete key, for example to remove a saved connection. Once it succeeds, the select removes the item
+	// from its Items, so the function shouldn't change them, and the next item gets highlighted. If it fails,
+	// its error is displayed below the list until the user presses a key and the item stays.
+	OnDelete func(index int) error
+
+	// OnReorder is an optional function called when the user moves the active item with the MoveUp or MoveDown
+	// keys, with its index in the Items and the index it moves to, for example to change the priority of a
+	// rule. Once it succeeds, the select moves the item in its Items, so the function shouldn't change them,
+	// and the item stays highlighted. Once Run returns, Items holds the items in their final order. If the
+	// function fails, its error is displayed below the list until the user presses a key and the item stays.
+	// The items can't be moved while the list is narrowed by a search or a tag.
+	OnReorder func(from, to int) error
+
+	// RequireDeleteConfirm sets whether the user confirms the deletion of an item with a confirm prompt before
+	// OnDelete is called. The prompt is displayed in place of the list and reads the Stdin of the select.
+	RequireDeleteConfirm bool
+
+	// Events is an optional handler notified of the changes of state of the select. See the EventHandler docs
+	// for more info.
+	Events EventHandler
+
+	// MouseEnabled sets whether the select can be used with the mouse, for terminals reporting mouse events
+	// with the SGR encoding like most modern ones do. The wheel moves up and down the list and clicking an item
+	// selects it. Mouse reporting is disabled again when the select returns. While it is enabled, the terminal
+	// doesn't let the user select text with the mouse.
+	MouseEnabled bool
+
+	// Recorder is an optional recorder of the keys read and the frames displayed by the select. See the
+	// Recorder docs for more info.
+	Recorder *Recorder
+
+	// DebugWriter is an optional writer receiving a copy of each frame displayed by the select, escape codes
+	// included, for example to snapshot its rendering or to make a recording of it. Each write holds one whole
+	// frame: the codes going back over the previous frame, the lines of the new one and the sequences written
+	// along with them. Unlike a Recorder, it receives neither the keys nor the codes written outside the
+	// frames, such as the ones hiding the cursor. Its errors are ignored.
+	DebugWriter io.Writer
+
+	// RedrawMode sets how the select is redrawn after each key. screenbuf.Incremental only rewrites the lines
+	// which changed, which reduces the flicker on slow terminals such as the ones reached over ssh. Defaults
+	// to screenbuf.Full, clearing all the lines before redrawing them.
+	RedrawMode screenbuf.RedrawMode
+
 	list *list.List
 
+	// detached is set for the runs of RunWith, see innerRun.
+	detached bool
+
+	// tag is the tag filtering the list, if any
+	tag string
+
+	// size is the number of items of the compact layout for the current run, the Size or the size fitting the
+	// terminal with AutoSize.
+	size int
+
+	// match is the match of the item being rendered, for the highlight template function.
+	match list.Match
+
 	// A function that determines how to render the cursor
 	Pointer Pointer
 
@@ -82,6 +253,25 @@ type Select struct {
 	Stdout io.WriteCloser
 }
 
+// EventHandler receives the changes of state of a select as they happen, such as the user moving to another item
+// or searching the list. It allows following the select from another part of a program, for example to mirror its
+// state into another UI. It has no effect on how the select is displayed.
+//
+// The methods are called from the goroutine reading the user input and should return quickly.
+type EventHandler interface {
+	// OnHighlight is called with the index of the item that became active inside the list, or list.NotFound
+	// when a search has no results. It is also called once with the initially active item.
+	OnHighlight(index int)
+
+	// OnSearch is called with the new search term each time it changes. It is called with an empty term
+	// when the search is cancelled.
+	OnSearch(query string)
+
+	// OnSelect is called with the index of the item the user selected, or SelectedNone, right before Run
+	// returns it.
+	OnSelect(index int)
+}
+
 // SelectKeys defines the available keys used by select mode to enable the user to move around the list
 // and trigger search mode. See the Key struct docs for more information on keys.
 type SelectKeys struct {
@@ -99,6 +289,52 @@ type SelectKeys struct {
 
 	// Search is the key used to trigger the search mode for the list. Default to the "/" key.
 	Search Key
+
+	// None is the key used to choose none of the items when AllowNone is set. Defaults to ctrl-x.
+	None Key
+
+	// Tag is the key used to filter the list by the next tag when the select has a TagFunc. Defaults to tab.
+	Tag Key
+
+	// Layout is the key used to switch between the compact and detailed layouts when the select has a Details
+	// template. Defaults to ctrl-t.
+	Layout Key
+
+	// Delete is the key used to delete the active item when the select has an OnDelete function. Defaults to
+	// the delete key, which is also sent by ctrl-d.
+	Delete Key
+
+	// MoveUp and MoveDown are the keys used to move the active item up and down when the select has an
+	// OnReorder function. Default to alt-up and alt-down.
+	MoveUp   Key
+	MoveDown Key
+
+	// Confirm is the key used to select the active item, for example ctrl-y to keep enter from closing a select
+	// which is always searching. Enter doesn't select anything once another key is set, unless it is one of
+	// its alternates. The help menu shows the key when it isn't enter. Defaults to enter.
+	Confirm Key
+}
+
+// DefaultSelectKeys is the set of keys used by a select when its Keys are nil. A copy of it can be modified to
+// change some of the keys while keeping the others, for example
+// 	keys := promptui.DefaultSelectKeys
+// 	keys.Search = promptui.Key{Code: '?', Display: "?"}
+// 	s := promptui.Select{Keys: &keys}
+//
+// Changing DefaultSelectKeys itself changes the keys of all the selects run afterwards.
+var DefaultSelectKeys = SelectKeys{
+	Prev:     Key{Code: KeyPrev, Display: KeyPrevDisplay},
+	Next:     Key{Code: KeyNext, Display: KeyNextDisplay},
+	PageUp:   Key{Code: KeyBackward, Display: KeyBackwardDisplay},
+	PageDown: Key{Code: KeyForward, Display: KeyForwardDisplay},
+	Search:   Key{Code: '/', Display: "/"},
+	None:     Key{Code: KeyNone, Display: KeyNoneDisplay},
+	Tag:      Key{Code: KeyTag, Display: KeyTagDisplay},
+	Layout:   Key{Code: KeyLayout, Display: KeyLayoutDisplay},
+	Delete:   Key{Code: KeyDelete, Display: KeyDeleteDisplay},
+	MoveUp:   Key{Code: KeyMoveUp, Display: KeyMoveUpDisplay},
+	MoveDown: Key{Code: KeyMoveDown, Display: KeyMoveDownDisplay},
+	Confirm:  Key{Code: KeyEnter, Display: KeyEnterDisplay},
 }
 
 // Key defines a keyboard code and a display representation for the help menu.
@@ -110,6 +346,36 @@ type Key struct {
 	// Display is the string that will be displayed inside the help menu to help inform the user
 	// of which key to use on his keyboard for various functions.
 	Display string
+
+	// Alternate is another key triggering the same function, for example ctrl-n on top of the down arrow key
+	// for moving down, which can have an Alternate of its own for more keys. The alternates are displayed
+	// after the key inside the help menu. Like the key, an alternate is matched while searching, so a
+	// printable one can't be typed in the search.
+	//
+	// Alternate is a pointer so that Key stays comparable with ==. Two keys are equal only when they point to
+	// the same Alternate: keys with distinct alternates of the same codes are different.
+	Alternate *Key
+}
+
+// Matches reports whether the typed key code triggers the function of the key, being its code or the code of
+// one of its alternates.
+func (k Key) Matches(code rune) bool {
+	if code == k.Code {
+		return true
+	}
+	return k.Alternate != nil && k.Alternate.Matches(code)
+}
+
+// HelpText returns the display of the key for the help menu, followed by the display of its alternates
+// separated by slashes.
+func (k Key) HelpText() string {
+	text := k.Display
+	if k.Alternate != nil {
+		if t := k.Alternate.HelpText(); t != "" {
+			text += "/" + t
+		}
+	}
+	return text
 }
 
 // SelectTemplates allow a select list to be customized following stdlib
@@ -137,9 +403,14 @@ type Key struct {
 //
 // Setting any of these templates will remove the icons from the default templates. They must
 // be added back in each of their specific templates. The styles.go constants contains the default icons.
+// To only change the icons of the items, the prefixes can be set instead of the templates.
 type SelectTemplates struct {
 	// Label is a text/template for the main command line label. Defaults to printing the label as it with
 	// the IconInitial.
+	//
+	// On top of the FuncMap, the label template can use the total function for the number of items and the
+	// filtered function for the number of items matching the current search. For example,
+	// `{{ .Name }} ({{ filtered }}/{{ total }} available)`.
 	Label string
 
 	// Active is a text/template for when an item is currently active within the list.
@@ -165,6 +436,37 @@ type SelectTemplates struct {
 	// it shows keys for movement and search.
 	Help string
 
+	// Error is a text/template for displaying the error returned through Reprompt by the OnSelect function of
+	// the select. It is displayed below the list and can have multiple lines.
+	Error string
+
+	// NoResults is a text/template for the message displayed in place of the items when none of them matches
+	// the search. It receives the search query and can have multiple lines. Defaults to
+	// 	'No results for "{{ . }}"'
+	NoResults string
+
+	// Annotation is a text/template for the annotations returned by the AnnotationFunc of the select, displayed
+	// after the items. It receives the annotation. Defaults to the annotation in faint text.
+	Annotation string
+
+	// ActivePrefix is the marker displayed before the active item by the default Active template. Defaults to
+	// the IconSelect.
+	ActivePrefix string
+
+	// ActivePointer is an optional function transforming the ActivePrefix each time the default Active template
+	// displays it, like the Pointer of a prompt transforms the characters under its cursor. For example,
+	// BlockCursor inverts the colors of the marker, and a pointer returning a different frame on each call
+	// animates it. Custom Active templates can display the transformed marker with the activePrefix function.
+	ActivePointer Pointer
+
+	// InactivePrefix is the marker displayed before the inactive items by the default Inactive template.
+	// Defaults to a space.
+	InactivePrefix string
+
+	// SelectedPrefix is the marker displayed before the selected item by the default Selected template.
+	// Defaults to the IconGood.
+	SelectedPrefix string
+
 	// FuncMap is a map of helper functions that can be used inside of templates according to the text/template
 	// documentation.
 	//
@@ -172,12 +474,15 @@ type SelectTemplates struct {
 	// is overridden, the colors functions must be added in the override from promptui.FuncMap to work.
 	FuncMap template.FuncMap
 
-	label    *template.Template
-	active   *template.Template
-	inactive *template.Template
-	selected *template.Template
-	details  *template.Template
-	help     *template.Template
+	label      *template.Template
+	active     *template.Template
+	inactive   *template.Template
+	selected   *template.Template
+	details    *template.Template
+	help       *template.Template
+	err        *template.Template
+	noResults  *template.Template
+	annotation *template.Template
 }
 
 // SearchPrompt is the prompt displayed in search mode.
@@ -202,27 +507,95 @@ func (s *Select) RunCursorAt(cursorPos, scroll int) (int, string, error) {
 		s.Size = 5
 	}
 
-	l, err := list.New(s.Items, s.Size)
+	s.setKeys()
+
+	err := s.prepareTemplates()
 	if err != nil {
 		return 0, "", err
 	}
-	l.Searcher = s.Searcher
-
-	s.list = l
 
-	s.setKeys()
+	s.size = s.Size
+	if s.AutoSize {
+		s.size = s.autoSize()
+	}
 
-	err = s.prepareTemplates()
+	l, err := list.New(s.Items, s.layoutSize())
 	if err != nil {
 		return 0, "", err
 	}
+	l.Searcher = s.Searcher
+	l.ScoredSearcher = s.ScoredSearcher
+	l.SortByScore = s.SortByScore
+	l.KeepSearchSpaces = s.KeepSearchSpaces
+	s.tag = ""
+
+	s.list = l
 	return s.innerRun(cursorPos, scroll, ' ')
 }
 
+// RunWith executes the select like Run, reading the keys from stdin and displaying the list on stdout for this
+// run only. The select itself is left untouched, which allows running the same select from several goroutines at
+// once, each with its own input and output, for example to serve several sessions. The Stdin and Stdout of the
+// select act as defaults, used when stdin or stdout is nil. RunWith doesn't close stdout.
+//
+// Since the select isn't changed, its Items and Layout don't reflect the changes made by the user during the run,
+// unlike with Run.
+func (s *Select) RunWith(stdin io.ReadCloser, stdout io.Writer) (int, string, error) {
+	run := *s
+	run.detached = true
+	if s.Templates != nil {
+		tpls := *s.Templates
+		run.Templates = &tpls
+	}
+	if stdin != nil {
+		run.Stdin = stdin
+	}
+	if stdout != nil {
+		run.Stdout = nopCloser{stdout}
+	}
+	return run.Run()
+}
+
+// nopCloser is a writer with a Close method doing nothing.
+type nopCloser struct {
+	io.Writer
+}
+
+func (nopCloser) Close() error {
+	return nil
+}
+
 func (s *Select) innerRun(cursorPos, scroll int, top rune) (int, string, error) {
+	stdin := readline.Stdin
+	if s.Stdin != nil {
+		stdin = s.Stdin
+	}
+	in := io.Reader(translateStdinWith(s.Recorder.stdin(stdin, false), s.sequences()))
+
+	var mouse *mouseReader
+	if s.MouseEnabled {
+		mouse = newMouseReader(in, s.Keys.Prev.Code, s.Keys.Next.Code)
+		in = mouse
+	}
+
+	// readline only stops reading after a few keys, enter among them, which the delete key is turned into so
+	// that the keys typed next aren't handled by the list until the item is deleted
+	deletes := &stopReader{stdin: in}
+	if s.OnDelete != nil {
+		deletes.watch(&s.Keys.Delete, KeyEnter)
+	}
+
+	feed := newKeyFeed(deletes)
+	defer feed.Close()
+
 	c := &readline.Config{
-		Stdin:  s.Stdin,
-		Stdout: s.Stdout,
+		Stdin:  feed,
+		Stdout: s.Recorder.stdout(s.Stdout),
+	}
+	if s.detached {
+		// readline keeps a single function to call when the terminal is resized, replaced by each run, which
+		// isn't safe for the runs of RunWith running at the same time. Their output is their own anyway.
+		c.FuncOnWidthChanged = func(func()) {}
 	}
 	err := c.Init()
 	if err != nil {
@@ -238,30 +611,186 @@ func (s *Select) innerRun(cursorPos, scroll int, top rune) (int, string, error)
 	c.HistoryLimit = -1
 	c.UniqueEditLine = true
 
-	rl, err := readline.NewEx(c)
+	var rl *readline.Instance
+
+	// deleting is set when the line ends to delete the active item
+	deleting := false
+
+	// the keys are read once, readline may still filter a key after the run is over
+	confirm := s.confirmKey()
+	del := s.Keys.Delete
+	canDelete := s.OnDelete != nil
+	c.FuncFilterInputRune = func(r rune) (rune, bool) {
+		switch {
+		case r == KeyEnter && deletes.pressed:
+			deleting = true
+			return KeyEnter, true
+		case r == keyConfirm, confirm.Matches(r):
+			return KeyEnter, true
+		case r == readline.CharCtrlJ && confirm.Matches(KeyEnter):
+			// piped input ends its lines with a line feed, which confirms along with enter
+			return KeyEnter, true
+		case canDelete && r != 0 && del.Matches(r):
+			// the line ends so that the item is deleted outside of the listener, which allows confirming it
+			// with a prompt
+			deleting = true
+			return KeyEnter, true
+		case r == KeyEnter, r == readline.CharCtrlJ:
+			// readline ends the line on enter, which isn't wanted when it isn't the confirm key. The terminal
+			// waits after an enter until the line is read, so it is told to go on reading.
+			rl.Terminal.KickRead()
+			return r, false
+		}
+		return r, true
+	}
+
+	// the hooks may run nested prompts, which take over the terminal until they are done. The runs of RunWith
+	// aren't nested in the others, which may run alongside them.
+	saveTerminal()
+	if !s.detached {
+		defer pushTerminalState(c.Stdout, s.MouseEnabled)()
+	}
+
+	rl, err = readline.NewEx(c)
 	if err != nil {
 		return 0, "", err
 	}
+	defer closeOnPanic(rl)
 
 	rl.Write([]byte(hideCursor))
-	sb := screenbuf.New(rl)
+	sb := screenbuf.New(debugFrames(rl, s.DebugWriter))
+	sb.SetMode(s.RedrawMode)
+
+	if mouse != nil {
+		rl.Write([]byte(mouseOn))
+		defer c.Stdout.Write([]byte(mouseOff))
+	}
+
+	// the last items received from Updates, not yet displayed
+	var (
+		updateMu sync.Mutex
+		updated  []interface{}
+		pending  bool
+	)
+
+	if s.Updates != nil {
+		done := make(chan struct{})
+		defer close(done)
+
+		go func() {
+			for {
+				select {
+				case items, ok := <-s.Updates:
+					if !ok {
+						return
+					}
+
+					updateMu.Lock()
+					updated, pending = items, true
+					updateMu.Unlock()
+					feed.Push(keyReload)
+				case <-done:
+					return
+				}
+			}
+		}()
+	}
+
+	// the line of the first item inside the select and the item displayed on each line from there, to locate
+	// the clicked items
+	itemsTop := 0
+	var itemRows []int
 
 	cur := NewCursor("", s.Pointer, false)
 
-	canSearch := s.Searcher != nil
+	canSearch := s.Searcher != nil || s.ScoredSearcher != nil
 	searchMode := s.StartInSearchMode
+	none := false
+
+	var selectErr error
+
+	// the last state sent to the event handler, the highlighted item is sent on the first render.
+	lastQuery := ""
+	lastHighlighted := list.NotFound - 1
 	s.list.SetCursor(cursorPos)
 	s.list.SetStart(scroll)
 
-	c.SetListener(func(line []rune, pos int, key rune) ([]rune, int, bool) {
+	guard := newLineGuard()
+	c.SetListener(guard.listen(func(line []rune, pos int, key rune) ([]rune, int, bool) {
+		defer restoreOnPanic(rl)
+
+		searched := false
+
+		// errors from the OnSelect function only stay displayed until the user presses a key
+		if key != 0 && key != keyReload {
+			selectErr = nil
+		}
+
 		switch {
+		case key == keyReload:
+			updateMu.Lock()
+			items, ok := updated, pending
+			pending = false
+			updateMu.Unlock()
+
+			if !ok {
+				break
+			}
+
+			search := ""
+			if searchMode {
+				search = cur.Get()
+			}
+
+			if err := s.updateItems(items, search); err != nil {
+				selectErr = err
+			}
+		case s.AllowNone && key != 0 && s.Keys.None.Matches(key):
+			none = true
+			feed.Push(keyConfirm)
+			return nil, 0, true
+		case s.Templates.details != nil && key != 0 && s.Keys.Layout.Matches(key):
+			if s.Layout == LayoutDetailed {
+				s.Layout = LayoutCompact
+			} else {
+				s.Layout = LayoutDetailed
+			}
+			s.list.SetSize(s.layoutSize())
+		case s.OnReorder != nil && key != 0 && (s.Keys.MoveUp.Matches(key) || s.Keys.MoveDown.Matches(key)):
+			if (searchMode && cur.Get() != "") || s.tag != "" {
+				break
+			}
+
+			shift := 1
+			if s.Keys.MoveUp.Matches(key) {
+				shift = -1
+			}
+			if err := s.moveItem(shift); err != nil {
+				selectErr = err
+			}
+		case s.TagFunc != nil && key != 0 && s.Keys.Tag.Matches(key):
+			s.tag = s.nextTag()
+			s.list.SetFilter(s.tagFilter())
+		case mouse != nil && key == keyMouseClick:
+			row, bottom, ok := mouse.click()
+			if !ok {
+				break
+			}
+
+			// the cursor is reported on the line below the select
+			i := row - (bottom - sb.Height()) - itemsTop
+			if i >= 0 && i < len(itemRows) {
+				s.list.SetCursor(s.list.Start() + itemRows[i])
+				feed.Push(keyConfirm)
+			}
 		case key == KeyEnter:
 			return nil, 0, true
-		case key == s.Keys.Next.Code || (key == 'j' && !searchMode):
+		// the vi keys aren't alternates of the default keys since they are typed in the search
+		case s.Keys.Next.Matches(key) || (key == 'j' && !searchMode):
 			s.list.Next()
-		case key == s.Keys.Prev.Code || (key == 'k' && !searchMode):
+		case s.Keys.Prev.Matches(key) || (key == 'k' && !searchMode):
 			s.list.Prev()
-		case key == s.Keys.Search.Code:
+		case s.Keys.Search.Matches(key):
 			if !canSearch {
 				break
 			}
@@ -281,65 +810,98 @@ func (s *Select) innerRun(cursorPos, scroll int, top rune) (int, string, error)
 			cur.Backspace()
 			if len(cur.Get()) > 0 {
 				s.list.Search(cur.Get())
+				searched = true
 			} else {
 				s.list.CancelSearch()
 			}
-		case key == s.Keys.PageUp.Code || (key == 'h' && !searchMode):
+		case s.Keys.PageUp.Matches(key) || (key == 'h' && !searchMode):
 			s.list.PageUp()
-		case key == s.Keys.PageDown.Code || (key == 'l' && !searchMode):
+		case s.Keys.PageDown.Matches(key) || (key == 'l' && !searchMode):
 			s.list.PageDown()
 		default:
 			if canSearch && searchMode {
 				cur.Update(string(line))
 				s.list.Search(cur.Get())
+				searched = true
+			}
+		}
+
+		if searched && s.AutoSelectSearch && s.list.Len() == 1 {
+			feed.Push(keyConfirm)
+		}
+
+		if s.Events != nil {
+			query := ""
+			if searchMode {
+				query = cur.Get()
+			}
+			if query != lastQuery {
+				lastQuery = query
+				s.Events.OnSearch(query)
+			}
+
+			highlighted := list.NotFound
+			if _, idx := s.list.Items(); idx != list.NotFound {
+				highlighted = s.list.Index()
+			}
+			if highlighted != lastHighlighted {
+				lastHighlighted = highlighted
+				s.Events.OnHighlight(highlighted)
 			}
 		}
 
+		itemsTop = 1
 		if searchMode {
 			header := SearchPrompt + cur.Format()
+			if s.tag != "" {
+				header += "  " + Styler(FGFaint)("tag:") + " " + Styler(FGCyan)(s.tag)
+			}
 			sb.WriteString(header)
+			itemsTop++
 		} else if !s.HideHelp {
 			help := s.renderHelp(canSearch)
 			sb.Write(help)
+			itemsTop++
 		}
 
 		label := render(s.Templates.label, s.Label)
 		sb.Write(label)
 
 		items, idx := s.list.Items()
-		last := len(items) - 1
+		lines := s.renderItems(top)
 
-		for i, item := range items {
-			page := " "
+		detailed := s.Layout == LayoutDetailed && s.Templates.details != nil
 
-			switch i {
-			case 0:
-				if s.list.CanPageUp() {
-					page = "↑"
-				} else {
-					page = string(top)
-				}
-			case last:
-				if s.list.CanPageDown() {
-					page = "↓"
+		itemRows = itemRows[:0]
+		for i, line := range lines {
+			sb.Write(line)
+			itemRows = append(itemRows, i)
+
+			if detailed {
+				for _, d := range s.renderDetails(items[i]) {
+					sb.Write(d)
+					itemRows = append(itemRows, i)
 				}
 			}
+		}
 
-			output := []byte(page + " ")
-
-			if i == idx {
-				output = append(output, render(s.Templates.active, item)...)
-			} else {
-				output = append(output, render(s.Templates.inactive, item)...)
+		if selectErr != nil {
+			for _, line := range bytes.Split(render(s.Templates.err, selectErr), []byte("\n")) {
+				sb.Write(line)
 			}
-
-			sb.Write(output)
 		}
 
 		if idx == list.NotFound {
+			var query string
+			if searchMode {
+				query = cur.Get()
+			}
+
 			sb.WriteString("")
-			sb.WriteString("No results")
-		} else {
+			for _, line := range bytes.Split(render(s.Templates.noResults, query), []byte("\n")) {
+				sb.Write(line)
+			}
+		} else if !detailed {
 			active := items[idx]
 
 			details := s.renderDetails(active)
@@ -348,29 +910,84 @@ func (s *Select) innerRun(cursorPos, scroll int, top rune) (int, string, error)
 			}
 		}
 
+		if mouse != nil {
+			sb.WriteSequence([]byte(queryCursor))
+		}
+
 		sb.Flush()
 
 		return nil, 0, true
-	})
+	}))
+
+	autoSelect := s.AutoSelectSingle && s.list.Len() == 1
 
 	for {
-		_, err = rl.Readline()
+		if autoSelect {
+			autoSelect = false
+		} else {
+			_, err = rl.Readline()
+			guard.wait(err)
+
+			if err != nil {
+				switch {
+				case err == readline.ErrInterrupt, err.Error() == "Interrupt":
+					err = ErrInterrupt
+				case err == io.EOF:
+					err = ErrEOF
+				}
+				break
+			}
 
-		if err != nil {
-			switch {
-			case err == readline.ErrInterrupt, err.Error() == "Interrupt":
-				err = ErrInterrupt
-			case err == io.EOF:
-				err = ErrEOF
+			if deleting {
+				deleting = false
+
+				search := ""
+				if searchMode {
+					search = cur.Get()
+				}
+
+				err = s.deleteItem(sb, deletes, search)
+				if e, ok := err.(*repromptError); ok {
+					selectErr = e.err
+				} else if err != nil {
+					break
+				}
+				rl.Write([]byte(hideCursor))
+				continue
+			}
+
+			_, idx := s.list.Items()
+			if !none && idx == list.NotFound {
+				continue
 			}
+		}
+
+		if none || s.OnSelect == nil {
 			break
 		}
 
-		_, idx := s.list.Items()
-		if idx != list.NotFound {
+		// the list is cleared so that the hook can display its own prompts in its place, the list is
+		// displayed again below them if the hook asks to reprompt.
+		clearScreen(sb)
+		err = s.OnSelect(s.list.Index())
+		rl.Write([]byte(hideCursor))
+
+		if e, ok := err.(*repromptError); ok {
+			selectErr = e.err
+		} else if err != ErrReprompt {
 			break
 		}
 
+		// the hook may have edited the items, so they are loaded again before displaying the list
+		search := ""
+		if searchMode {
+			search = cur.Get()
+		}
+
+		err = s.reloadItems(search)
+		if err != nil {
+			break
+		}
 	}
 
 	if err != nil {
@@ -379,15 +996,40 @@ func (s *Select) innerRun(cursorPos, scroll int, top rune) (int, string, error)
 		}
 		sb.Reset()
 		sb.WriteString("")
+		sb.WriteSequence([]byte(showCursor))
 		sb.Flush()
-		rl.Write([]byte(showCursor))
 		rl.Close()
 		return 0, "", err
 	}
 
+	if none {
+		label := s.NoneLabel
+		if label == "" {
+			label = "None"
+		}
+
+		sb.WriteSequence([]byte(showCursor))
+		if s.HideSelected {
+			clearScreen(sb)
+		} else {
+			sb.Reset()
+			sb.Write(render(s.Templates.selected, label))
+			sb.Flush()
+		}
+
+		rl.Close()
+
+		if s.Events != nil {
+			s.Events.OnSelect(SelectedNone)
+		}
+
+		return SelectedNone, "", nil
+	}
+
 	items, idx := s.list.Items()
 	item := items[idx]
 
+	sb.WriteSequence([]byte(showCursor))
 	if s.HideSelected {
 		clearScreen(sb)
 	} else {
@@ -396,10 +1038,263 @@ func (s *Select) innerRun(cursorPos, scroll int, top rune) (int, string, error)
 		sb.Flush()
 	}
 
-	rl.Write([]byte(showCursor))
 	rl.Close()
 
-	return s.list.Index(), fmt.Sprintf("%v", item), err
+	if s.Events != nil {
+		s.Events.OnSelect(s.list.Index())
+	}
+
+	value := fmt.Sprintf("%v", item)
+	if err == nil {
+		err = copyToClipboard(s.CopyToClipboard, value)
+	}
+
+	return s.list.Index(), value, err
+}
+
+// SelectChoose returns what the given select's Run would return if the user chose the item at the given index,
+// without displaying anything or reading from the terminal. It can be used to drive a select from a script or
+// inside tests.
+//
+// An error is returned if the select's items are invalid or if the index is out of their range.
+func SelectChoose(s Select, index int) (int, string, error) {
+	size := s.Size
+	if size == 0 {
+		size = 5
+	}
+
+	l, err := list.New(s.Items, size)
+	if err != nil {
+		return 0, "", err
+	}
+
+	if index < 0 || index >= l.Len() {
+		return 0, "", fmt.Errorf("index %d is out of range for %d items", index, l.Len())
+	}
+
+	l.SetCursor(index)
+	items, idx := l.Items()
+
+	return l.Index(), fmt.Sprintf("%v", items[idx]), nil
+}
+
+// RenderSelectPreview returns the lines the given select displays when it starts, with the cursor on the first
+// item, without displaying anything or reading from the terminal. It goes through the same templates as Run,
+// which makes it suitable for documentation and for snapshot tests of custom templates. Each line ends with a
+// line break, and the text holds the escape codes of the styles used by the templates.
+//
+// An error is returned if the templates or the select's items are invalid.
+func RenderSelectPreview(s Select) (string, error) {
+	if s.Size == 0 {
+		s.Size = 5
+	}
+
+	// the templates are prepared on a copy, leaving the ones of the caller untouched
+	if s.Templates != nil {
+		tpls := *s.Templates
+		s.Templates = &tpls
+	}
+
+	s.setKeys()
+
+	err := s.prepareTemplates()
+	if err != nil {
+		return "", err
+	}
+
+	s.size = s.Size
+	l, err := list.New(s.Items, s.layoutSize())
+	if err != nil {
+		return "", err
+	}
+	s.list = l
+
+	var buf bytes.Buffer
+	writeLine := func(line []byte) {
+		buf.Write(line)
+		buf.WriteByte('\n')
+	}
+
+	canSearch := s.Searcher != nil || s.ScoredSearcher != nil
+	if s.StartInSearchMode {
+		cur := NewCursor("", s.Pointer, false)
+		writeLine([]byte(SearchPrompt + cur.Format()))
+	} else if !s.HideHelp {
+		writeLine(s.renderHelp(canSearch))
+	}
+
+	for _, line := range bytes.Split(render(s.Templates.label, s.Label), []byte("\n")) {
+		writeLine(line)
+	}
+
+	items, idx := s.list.Items()
+	detailed := s.Layout == LayoutDetailed && s.Templates.details != nil
+
+	for i, line := range s.renderItems(' ') {
+		writeLine(line)
+
+		if detailed {
+			for _, d := range s.renderDetails(items[i]) {
+				writeLine(d)
+			}
+		}
+	}
+
+	if idx == list.NotFound {
+		writeLine(nil)
+		for _, line := range bytes.Split(render(s.Templates.noResults, ""), []byte("\n")) {
+			writeLine(line)
+		}
+	} else if !detailed {
+		for _, d := range s.renderDetails(items[idx]) {
+			writeLine(d)
+		}
+	}
+
+	return buf.String(), nil
+}
+
+// repromptError is the error created by Reprompt.
+type repromptError struct {
+	err error
+}
+
+func (e *repromptError) Error() string {
+	return e.err.Error()
+}
+
+// Reprompt creates an error for the OnSelect function of a select. It keeps the select open like ErrReprompt
+// and displays the given error below the list until the user presses a key.
+func Reprompt(err error) error {
+	return &repromptError{err: err}
+}
+
+// reloadItems creates the list again from the current items, keeping its cursor and scroll positions. If search
+// is not empty, the new list is filtered by it.
+func (s *Select) reloadItems(search string) error {
+	l, err := list.New(s.Items, s.layoutSize())
+	if err != nil {
+		return err
+	}
+	l.Searcher = s.Searcher
+	l.ScoredSearcher = s.ScoredSearcher
+	l.SortByScore = s.SortByScore
+	l.KeepSearchSpaces = s.KeepSearchSpaces
+	l.SetFilter(s.tagFilter())
+
+	if search != "" {
+		l.Search(search)
+	}
+
+	_, idx := s.list.Items()
+	start := s.list.Start()
+	l.SetCursor(start + idx)
+	l.SetStart(start)
+
+	s.list = l
+	return nil
+}
+
+// deleteItem deletes the active item with OnDelete, once the user confirmed it if RequireDeleteConfirm is set,
+// and removes it from the Items. The list is then reloaded, filtered by search if it is not empty. The error of
+// OnDelete is returned as a Reprompt error to display it, the other errors end the select.
+func (s *Select) deleteItem(sb *screenbuf.ScreenBuf, stdin *stopReader, search string) error {
+	// the keys typed next are back to the list once the item is deleted
+	defer stdin.watch(&s.Keys.Delete, KeyEnter)
+
+	items, idx := s.list.Items()
+	if idx == list.NotFound {
+		return nil
+	}
+	index := s.list.Index()
+
+	if s.RequireDeleteConfirm {
+		// the prompt is displayed in place of the list, which is displayed again from scratch once it is done
+		clearScreen(sb)
+		// the prompt reads the keys left by the list, up to the enter answering it
+		stdin.watch(&Key{Code: KeyEnter, Alternate: &Key{Code: readline.CharCtrlJ}}, KeyEnter)
+		p := Prompt{
+			Label:       fmt.Sprintf("Delete %v", items[idx]),
+			IsConfirm:   true,
+			HideEntered: true,
+			Stdin:       ioutil.NopCloser(stdin),
+			Stdout:      s.Stdout,
+		}
+		_, err := p.Run()
+		if err == ErrAbort {
+			return nil
+		}
+		if err != nil {
+			return err
+		}
+	}
+
+	if err := s.OnDelete(index); err != nil {
+		return Reprompt(err)
+	}
+
+	v := reflect.ValueOf(s.Items)
+	rest := reflect.MakeSlice(v.Type(), 0, v.Len()-1)
+	rest = reflect.AppendSlice(rest, v.Slice(0, index))
+	rest = reflect.AppendSlice(rest, v.Slice(index+1, v.Len()))
+	s.Items = rest.Interface()
+
+	return s.reloadItems(search)
+}
+
+// moveItem moves the active item by shift positions in the Items with OnReorder, keeping it highlighted. The
+// list must not be narrowed by a search or a tag.
+func (s *Select) moveItem(shift int) error {
+	if _, idx := s.list.Items(); idx == list.NotFound {
+		return nil
+	}
+
+	from := s.list.Index()
+	to := from + shift
+	v := reflect.ValueOf(s.Items)
+	if to < 0 || to >= v.Len() {
+		return nil
+	}
+
+	if err := s.OnReorder(from, to); err != nil {
+		return err
+	}
+
+	moved := reflect.MakeSlice(v.Type(), v.Len(), v.Len())
+	reflect.Copy(moved, v)
+	reflect.Swapper(moved.Interface())(from, to)
+	s.Items = moved.Interface()
+
+	if err := s.reloadItems(""); err != nil {
+		return err
+	}
+	s.list.SetCursor(to)
+	return nil
+}
+
+// updateItems replaces the items of the list, keeping the highlighted item highlighted at the same row if it is
+// found among the new items.
+func (s *Select) updateItems(items []interface{}, search string) error {
+	visible, row := s.list.Items()
+	var highlighted interface{}
+	if row != list.NotFound {
+		highlighted = visible[row]
+	}
+
+	s.Items = items
+	err := s.reloadItems(search)
+	if err != nil || row == list.NotFound {
+		return err
+	}
+
+	for pos, i := range s.list.Indexes() {
+		if reflect.DeepEqual(items[i], highlighted) {
+			s.list.SetCursor(pos)
+			s.list.SetStart(pos - row)
+			break
+		}
+	}
+	return nil
 }
 
 // ScrollPosition returns the current scroll position.
@@ -421,47 +1316,69 @@ func (s *Select) prepareTemplates() error {
 		tpls.Label = fmt.Sprintf("%s {{.}}: ", IconInitial)
 	}
 
-	tpl, err := template.New("").Funcs(tpls.FuncMap).Parse(tpls.Label)
+	tpl, err := template.New("").Funcs(tpls.FuncMap).Funcs(s.commonFuncs()).Funcs(s.labelFuncs()).Parse(tpls.Label)
 	if err != nil {
 		return err
 	}
 
 	tpls.label = tpl
 
+	if tpls.ActivePrefix == "" {
+		tpls.ActivePrefix = IconSelect
+	}
+
 	if tpls.Active == "" {
-		tpls.Active = fmt.Sprintf("%s {{ . | underline }}", IconSelect)
+		tpls.Active = fmt.Sprintf("{{ %q }} {{ . | underline }}", tpls.ActivePrefix)
+		if tpls.ActivePointer != nil {
+			tpls.Active = "{{ activePrefix }} {{ . | underline }}"
+		}
 	}
 
-	tpl, err = template.New("").Funcs(tpls.FuncMap).Parse(tpls.Active)
+	tpl, err = template.New("").Funcs(tpls.FuncMap).Funcs(s.commonFuncs()).Funcs(s.matchFuncs()).Funcs(template.FuncMap{
+		"activePrefix": func() string {
+			if tpls.ActivePointer == nil {
+				return tpls.ActivePrefix
+			}
+			return string(tpls.ActivePointer([]rune(tpls.ActivePrefix)))
+		},
+	}).Parse(tpls.Active)
 	if err != nil {
 		return err
 	}
 
 	tpls.active = tpl
 
+	if tpls.InactivePrefix == "" {
+		tpls.InactivePrefix = " "
+	}
+
 	if tpls.Inactive == "" {
-		tpls.Inactive = "  {{.}}"
+		tpls.Inactive = fmt.Sprintf("{{ %q }} {{.}}", tpls.InactivePrefix)
 	}
 
-	tpl, err = template.New("").Funcs(tpls.FuncMap).Parse(tpls.Inactive)
+	tpl, err = template.New("").Funcs(tpls.FuncMap).Funcs(s.commonFuncs()).Funcs(s.matchFuncs()).Parse(tpls.Inactive)
 	if err != nil {
 		return err
 	}
 
 	tpls.inactive = tpl
 
+	if tpls.SelectedPrefix == "" {
+		tpls.SelectedPrefix = IconGood
+	}
+
 	if tpls.Selected == "" {
-		tpls.Selected = fmt.Sprintf(`{{ "%s" | green }} {{ . | faint }}`, IconGood)
+		tpls.Selected = fmt.Sprintf(`{{ %q | green }} {{ . | faint }}`, tpls.SelectedPrefix)
 	}
 
-	tpl, err = template.New("").Funcs(tpls.FuncMap).Parse(tpls.Selected)
+	tpl, err = template.New("").Funcs(tpls.FuncMap).Funcs(s.commonFuncs()).Parse(tpls.Selected)
 	if err != nil {
 		return err
 	}
 	tpls.selected = tpl
 
 	if tpls.Details != "" {
-		tpl, err = template.New("").Funcs(tpls.FuncMap).Parse(tpls.Details)
+		tpl, err = template.New("").Funcs(tpls.FuncMap).Funcs(s.commonFuncs()).Parse(tpls.Details)
 		if err != nil {
 			return err
 		}
@@ -472,16 +1389,56 @@ func (s *Select) prepareTemplates() error {
 	if tpls.Help == "" {
 		tpls.Help = fmt.Sprintf(`{{ "Use the arrow keys to navigate:" | faint }} {{ .NextKey | faint }} ` +
 			`{{ .PrevKey | faint }} {{ .PageDownKey | faint }} {{ .PageUpKey | faint }} ` +
-			`{{ if .Search }} {{ "and" | faint }} {{ .SearchKey | faint }} {{ "toggles search" | faint }}{{ end }}`)
+			`{{ if .Search }} {{ "and" | faint }} {{ .SearchKey | faint }} {{ "toggles search" | faint }}{{ end }}` +
+			`{{ if .None }} {{ .NoneKey | faint }} {{ "selects none" | faint }}{{ end }}` +
+			`{{ if .Tags }} {{ .TagKey | faint }} {{ "filters by tag" | faint }}{{ end }}` +
+			`{{ if .Layout }} {{ .LayoutKey | faint }} {{ "toggles details" | faint }}{{ end }}` +
+			`{{ if .Delete }} {{ .DeleteKey | faint }} {{ "deletes" | faint }}{{ end }}` +
+			`{{ if .Reorder }} {{ .MoveUpKey | faint }} {{ .MoveDownKey | faint }} {{ "moves" | faint }}{{ end }}` +
+			`{{ if .Confirm }} {{ .ConfirmKey | faint }} {{ "confirms" | faint }}{{ end }}` +
+			`{{ with .Tag }}  {{ "tag:" | faint }} {{ . | cyan }}{{ end }}`)
 	}
 
-	tpl, err = template.New("").Funcs(tpls.FuncMap).Parse(tpls.Help)
+	tpl, err = template.New("").Funcs(tpls.FuncMap).Funcs(s.commonFuncs()).Parse(tpls.Help)
 	if err != nil {
 		return err
 	}
 
 	tpls.help = tpl
 
+	if tpls.Error == "" {
+		tpls.Error = `{{ ">>" | red }} {{ . | red }}`
+	}
+
+	tpl, err = template.New("").Funcs(tpls.FuncMap).Funcs(s.commonFuncs()).Parse(tpls.Error)
+	if err != nil {
+		return err
+	}
+
+	tpls.err = tpl
+
+	if tpls.NoResults == "" {
+		tpls.NoResults = `No results{{ with . }} for "{{ . }}"{{ end }}`
+	}
+
+	tpl, err = template.New("").Funcs(tpls.FuncMap).Funcs(s.commonFuncs()).Parse(tpls.NoResults)
+	if err != nil {
+		return err
+	}
+
+	tpls.noResults = tpl
+
+	if tpls.Annotation == "" {
+		tpls.Annotation = `{{ . | faint }}`
+	}
+
+	tpl, err = template.New("").Funcs(tpls.FuncMap).Funcs(s.commonFuncs()).Parse(tpls.Annotation)
+	if err != nil {
+		return err
+	}
+
+	tpls.annotation = tpl
+
 	s.Templates = tpls
 
 	return nil
@@ -539,6 +1496,7 @@ func (sa *SelectWithAdd) Run() (int, string, error) {
 			IsVimMode: sa.IsVimMode,
 			HideHelp:  sa.HideHelp,
 			Size:      5,
+			size:      5,
 			list:      list,
 			Pointer:   sa.Pointer,
 		}
@@ -572,15 +1530,281 @@ func (s *Select) setKeys() {
 	if s.Keys != nil {
 		return
 	}
-	s.Keys = &SelectKeys{
-		Prev:     Key{Code: KeyPrev, Display: KeyPrevDisplay},
-		Next:     Key{Code: KeyNext, Display: KeyNextDisplay},
-		PageUp:   Key{Code: KeyBackward, Display: KeyBackwardDisplay},
-		PageDown: Key{Code: KeyForward, Display: KeyForwardDisplay},
-		Search:   Key{Code: '/', Display: "/"},
+	keys := DefaultSelectKeys
+	s.Keys = &keys
+}
+
+// sequences returns the escape sequences to translate when reading the keys of the select: the KeySequences,
+// along with the ones of alt-up and alt-down when they move the items.
+func (s *Select) sequences() map[string]rune {
+	if s.OnReorder == nil {
+		return KeySequences
+	}
+
+	sequences := make(map[string]rune, len(KeySequences)+len(moveSequences))
+	for seq, code := range moveSequences {
+		if s.Keys.MoveUp.Matches(code) || s.Keys.MoveDown.Matches(code) {
+			sequences[seq] = code
+		}
+	}
+	for seq, code := range KeySequences {
+		sequences[seq] = code
+	}
+	return sequences
+}
+
+// confirmKey returns the Confirm key of the select, enter if the Keys don't set it.
+func (s *Select) confirmKey() Key {
+	if s.Keys.Confirm.Code == 0 {
+		return Key{Code: KeyEnter, Display: KeyEnterDisplay}
+	}
+	return s.Keys.Confirm
+}
+
+// commonFuncs returns the template functions available inside all the templates, giving access to the
+// TemplateData and the Layout.
+func (s *Select) commonFuncs() template.FuncMap {
+	return template.FuncMap{
+		"data": func(key string) interface{} {
+			return s.TemplateData[key]
+		},
+		"detailed": func() bool {
+			return s.Layout == LayoutDetailed
+		},
+	}
+}
+
+// nextTag returns the tag following the current one among the tags of the items, in the order they first appear,
+// or an empty string after the last one.
+func (s *Select) nextTag() string {
+	var tags []string
+	seen := make(map[string]bool)
+
+	items := reflect.ValueOf(s.Items)
+	for i := 0; i < items.Len(); i++ {
+		for _, tag := range s.TagFunc(i) {
+			if tag != "" && !seen[tag] {
+				seen[tag] = true
+				tags = append(tags, tag)
+			}
+		}
+	}
+
+	if s.tag == "" {
+		if len(tags) == 0 {
+			return ""
+		}
+		return tags[0]
+	}
+
+	for i, tag := range tags {
+		if tag == s.tag && i+1 < len(tags) {
+			return tags[i+1]
+		}
+	}
+	return ""
+}
+
+// tagFilter returns the filter of the list keeping the items having the current tag, or nil if there is no
+// current tag.
+func (s *Select) tagFilter() func(index int) bool {
+	if s.tag == "" {
+		return nil
+	}
+
+	current := s.tag
+	return func(index int) bool {
+		for _, tag := range s.TagFunc(index) {
+			if tag == current {
+				return true
+			}
+		}
+		return false
+	}
+}
+
+// layoutSize returns the number of items displayed by the list in the current layout. The detailed layout shows
+// as many items as fit in the lines of the compact layout, as told by the details of the first item.
+func (s *Select) layoutSize() int {
+	if s.Layout != LayoutDetailed || s.Templates.details == nil {
+		return s.size
+	}
+
+	lines := 1
+	items := reflect.ValueOf(s.Items)
+	if items.Kind() == reflect.Slice && items.Len() > 0 {
+		lines += len(s.renderDetails(items.Index(0).Interface()))
+	}
+
+	if s.size/lines < 1 {
+		return 1
+	}
+	return s.size / lines
+}
+
+// labelFuncs returns the template functions giving access to the state of the list inside the label template.
+func (s *Select) labelFuncs() template.FuncMap {
+	return template.FuncMap{
+		"total": func() int {
+			v := reflect.ValueOf(s.Items)
+			if v.Kind() != reflect.Slice {
+				return 0
+			}
+			return v.Len()
+		},
+		"filtered": func() int {
+			if s.list == nil {
+				return 0
+			}
+			return s.list.Len()
+		},
+	}
+}
+
+// matchFuncs returns the template functions giving access to the match of the item being rendered inside the item
+// templates.
+func (s *Select) matchFuncs() template.FuncMap {
+	return template.FuncMap{
+		"highlight": func(v interface{}) string {
+			return highlight(fmt.Sprint(v), s.match.Ranges)
+		},
 	}
 }
 
+// highlight emphasizes the given ranges of the text in bold. The ranges out of the text are ignored.
+func highlight(text string, ranges []list.Range) string {
+	runes := []rune(text)
+
+	var b strings.Builder
+	last := 0
+	for _, r := range ranges {
+		if r.Start < last || r.End > len(runes) || r.Start >= r.End {
+			continue
+		}
+		b.WriteString(string(runes[last:r.Start]))
+		b.WriteString(esc + "1m" + string(runes[r.Start:r.End]) + esc + "22m")
+		last = r.End
+	}
+	b.WriteString(string(runes[last:]))
+
+	return b.String()
+}
+
+// autoSize returns the number of items fitting in the terminal along with the other lines of the select, or Size
+// if the height of the terminal is unknown.
+func (s *Select) autoSize() int {
+	height, ok := terminalHeight(s.Stdout)
+	if !ok {
+		return s.Size
+	}
+
+	// the label and the line left below the select
+	lines := 2
+	if !s.HideHelp || s.Searcher != nil || s.ScoredSearcher != nil {
+		lines++
+	}
+
+	items := reflect.ValueOf(s.Items)
+	if items.Kind() == reflect.Slice && items.Len() > 0 {
+		lines += len(s.renderDetails(items.Index(0).Interface()))
+	}
+
+	if height-lines < 1 {
+		return 1
+	}
+	return height - lines
+}
+
+// terminalHeight returns the height of the terminal the given output is written to, if it is one.
+var terminalHeight = func(out io.Writer) (int, bool) {
+	if out == nil {
+		out = readline.Stdout
+	}
+
+	f, ok := out.(*os.File)
+	if !ok {
+		return 0, false
+	}
+
+	_, height, err := terminalSize(int(f.Fd()))
+	if err != nil || height <= 0 {
+		return 0, false
+	}
+	return height, true
+}
+
+// annotate appends the annotations of the items to their lines, given the indexes of the items in the Items. The
+// annotations are right-aligned in a column, past the widest line.
+func (s *Select) annotate(lines [][]byte, indexes []int) [][]byte {
+	var buf bytes.Buffer
+	w := ansiterm.NewTabWriter(&buf, 0, 0, 1, ' ', 0)
+	w.SetColumnAlignRight(1)
+
+	// every line has the column of the annotations, so that the lines without one don't split the column
+	for i, line := range lines {
+		w.Write(line)
+		w.Write([]byte("\t"))
+		if annotation := s.AnnotationFunc(indexes[i]); annotation != "" {
+			w.Write(render(s.Templates.annotation, annotation))
+		}
+		w.Write([]byte("\t\n"))
+	}
+
+	w.Flush()
+
+	annotated := bytes.Split(bytes.TrimSuffix(buf.Bytes(), []byte("\n")), []byte("\n"))
+	for i, line := range annotated {
+		annotated[i] = bytes.TrimRight(line, " ")
+	}
+
+	return annotated
+}
+
+// renderItems renders the visible items of the list, one line each, with the page markers before them. top is
+// the marker of the first item when the list can't page up.
+func (s *Select) renderItems(top rune) [][]byte {
+	items, idx := s.list.Items()
+	matches := s.list.Matches()
+	last := len(items) - 1
+
+	lines := make([][]byte, 0, len(items))
+
+	for i, item := range items {
+		s.match = matches[i]
+
+		page := " "
+
+		switch i {
+		case 0:
+			if s.list.CanPageUp() {
+				page = "↑"
+			} else {
+				page = string(top)
+			}
+		case last:
+			if s.list.CanPageDown() {
+				page = "↓"
+			}
+		}
+
+		output := []byte(page + " ")
+
+		if i == idx {
+			output = append(output, render(s.Templates.active, item)...)
+		} else {
+			output = append(output, render(s.Templates.inactive, item)...)
+		}
+
+		lines = append(lines, output)
+	}
+
+	if s.AnnotationFunc != nil {
+		lines = s.annotate(lines, s.list.Indexes()[s.list.Start():])
+	}
+
+	return lines
+}
+
 func (s *Select) renderDetails(item interface{}) [][]byte {
 	if s.Templates.details == nil {
 		return nil
@@ -609,13 +1833,41 @@ func (s *Select) renderHelp(b bool) []byte {
 		PageUpKey   string
 		Search      bool
 		SearchKey   string
+		None        bool
+		NoneKey     string
+		Tags        bool
+		TagKey      string
+		Tag         string
+		Layout      bool
+		LayoutKey   string
+		Delete      bool
+		DeleteKey   string
+		Reorder     bool
+		MoveUpKey   string
+		MoveDownKey string
+		Confirm     bool
+		ConfirmKey  string
 	}{
-		NextKey:     s.Keys.Next.Display,
-		PrevKey:     s.Keys.Prev.Display,
-		PageDownKey: s.Keys.PageDown.Display,
-		PageUpKey:   s.Keys.PageUp.Display,
-		SearchKey:   s.Keys.Search.Display,
+		NextKey:     s.Keys.Next.HelpText(),
+		PrevKey:     s.Keys.Prev.HelpText(),
+		PageDownKey: s.Keys.PageDown.HelpText(),
+		PageUpKey:   s.Keys.PageUp.HelpText(),
+		SearchKey:   s.Keys.Search.HelpText(),
 		Search:      b,
+		NoneKey:     s.Keys.None.HelpText(),
+		None:        s.AllowNone,
+		Tags:        s.TagFunc != nil,
+		TagKey:      s.Keys.Tag.HelpText(),
+		Tag:         s.tag,
+		Layout:      s.Templates.details != nil,
+		LayoutKey:   s.Keys.Layout.HelpText(),
+		Delete:      s.OnDelete != nil,
+		DeleteKey:   s.Keys.Delete.HelpText(),
+		Reorder:     s.OnReorder != nil,
+		MoveUpKey:   s.Keys.MoveUp.HelpText(),
+		MoveDownKey: s.Keys.MoveDown.HelpText(),
+		Confirm:     s.confirmKey().Code != KeyEnter,
+		ConfirmKey:  s.confirmKey().HelpText(),
 	}
 
 	return render(s.Templates.help, keys)
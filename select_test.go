@@ -2,8 +2,17 @@ package promptui
 
 import (
 	"bytes"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"reflect"
+	"strings"
+	"sync"
 	"testing"
+	"testing/iotest"
+	"time"
 
+	"github.com/manifoldco/promptui/list"
 	"github.com/manifoldco/promptui/screenbuf"
 )
 
@@ -112,6 +121,41 @@ Description: {{.Description}}`,
 		}
 	})
 
+	t.Run("when using custom prefixes", func(t *testing.T) {
+		values := []string{"Zero"}
+		s := Select{
+			Label: "Select Number",
+			Items: values,
+			Templates: &SelectTemplates{
+				ActivePrefix:   ">",
+				InactivePrefix: "-",
+				SelectedPrefix: `"ok"`,
+			},
+		}
+		err := s.prepareTemplates()
+		if err != nil {
+			t.Fatalf("Unexpected error preparing templates %v", err)
+		}
+
+		result := string(render(s.Templates.active, values[0]))
+		exp := "> \x1b[4mZero\x1b[0m"
+		if result != exp {
+			t.Errorf("Expected active item to eq %q, got %q", exp, result)
+		}
+
+		result = string(render(s.Templates.inactive, values[0]))
+		exp = "- Zero"
+		if result != exp {
+			t.Errorf("Expected inactive item to eq %q, got %q", exp, result)
+		}
+
+		result = string(render(s.Templates.selected, values[0]))
+		exp = "\x1b[32m\"ok\"\x1b[0m \x1b[2mZero\x1b[0m"
+		if result != exp {
+			t.Errorf("Expected selected item to eq %q, got %q", exp, result)
+		}
+	})
+
 	t.Run("when a template is invalid", func(t *testing.T) {
 		templates := &SelectTemplates{
 			Label: "{{ . ",
@@ -166,3 +210,1738 @@ func TestClearScreen(t *testing.T) {
 		t.Errorf("expected %q, got %q", except, got)
 	}
 }
+
+type nopWriteCloser struct {
+	bytes.Buffer
+}
+
+func (*nopWriteCloser) Close() error { return nil }
+
+// scriptedStdin types the given keys, then waits a second for the prompt to finish on its own
+// before reporting EOF.
+func scriptedStdin(keys string) io.ReadCloser {
+	return ioutil.NopCloser(io.MultiReader(strings.NewReader(keys), idleReader{}))
+}
+
+type idleReader struct{}
+
+func (idleReader) Read(p []byte) (int, error) {
+	time.Sleep(time.Second)
+	return 0, io.EOF
+}
+
+// scriptedSelect sets up the select to read the given keys as if they were typed by the user.
+func scriptedSelect(s *Select, keys string) *nopWriteCloser {
+	out := &nopWriteCloser{}
+	s.Stdin = scriptedStdin(keys)
+	s.Stdout = out
+	return out
+}
+
+func TestSelectAutoSelect(t *testing.T) {
+	t.Run("when items contains a single item", func(t *testing.T) {
+		s := Select{
+			Label:            "Select Number",
+			Items:            []string{"Zero"},
+			AutoSelectSingle: true,
+		}
+		scriptedSelect(&s, "")
+
+		idx, value, err := s.Run()
+		if err != nil {
+			t.Fatalf("Unexpected error %v", err)
+		}
+
+		if idx != 0 || value != "Zero" {
+			t.Errorf("Expected (0, %q), got (%d, %q)", "Zero", idx, value)
+		}
+	})
+
+	t.Run("when a search narrows the items to a single item", func(t *testing.T) {
+		items := []string{"Zero", "One", "Two", "Three"}
+		s := Select{
+			Label:            "Select Number",
+			Items:            items,
+			AutoSelectSearch: true,
+			Searcher: func(input string, index int) bool {
+				return strings.Contains(items[index], input)
+			},
+		}
+		scriptedSelect(&s, "/Th")
+
+		idx, value, err := s.Run()
+		if err != nil {
+			t.Fatalf("Unexpected error %v", err)
+		}
+
+		if idx != 3 || value != "Three" {
+			t.Errorf("Expected (3, %q), got (%d, %q)", "Three", idx, value)
+		}
+	})
+}
+
+func TestSelectSearchUniqueMatch(t *testing.T) {
+	items := []string{"Zero", "One", "Two", "Three"}
+	s := Select{
+		Label: "Select Number",
+		Items: items,
+		Searcher: func(input string, index int) bool {
+			return strings.Contains(items[index], input)
+		},
+	}
+
+	// search for "T", move down to "Three", then narrow the search down to "Two" only
+	scriptedSelect(&s, "/T\x1b[Bw\r")
+
+	idx, value, err := s.Run()
+	if err != nil {
+		t.Fatalf("Unexpected error %v", err)
+	}
+
+	if idx != 2 || value != "Two" {
+		t.Errorf("Expected (2, %q), got (%d, %q)", "Two", idx, value)
+	}
+}
+
+func TestSelectMixedItems(t *testing.T) {
+	type server struct {
+		Name   string
+		Region string
+	}
+
+	type cluster struct {
+		Name  string
+		Nodes int
+	}
+
+	items := []interface{}{
+		server{Name: "web", Region: "us-east-1"},
+		cluster{Name: "workers", Nodes: 3},
+		server{Name: "db", Region: "eu-west-1"},
+	}
+
+	templates := &SelectTemplates{
+		Active:   `{{ field . "Name" }} {{ field . "Region" }}{{ field . "Nodes" }}`,
+		Inactive: `{{ field . "Name" }} {{ field . "Region" }}{{ field . "Nodes" }}`,
+		Selected: `{{ field . "Name" }}`,
+	}
+
+	s := Select{
+		Label:     "Target",
+		Items:     items,
+		Templates: templates,
+		CursorPos: 1,
+		Searcher: func(input string, index int) bool {
+			name := Field(items[index], "Name").(string)
+			return strings.HasPrefix(name, input)
+		},
+	}
+
+	err := s.prepareTemplates()
+	if err != nil {
+		t.Fatalf("Unexpected error preparing templates %v", err)
+	}
+
+	result := string(render(s.Templates.active, items[0]))
+	exp := "web us-east-1"
+	if result != exp {
+		t.Errorf("Expected active item to eq %q, got %q", exp, result)
+	}
+
+	result = string(render(s.Templates.inactive, items[1]))
+	exp = "workers 3"
+	if result != exp {
+		t.Errorf("Expected inactive item to eq %q, got %q", exp, result)
+	}
+
+	scriptedSelect(&s, "\x1b[B\r")
+
+	idx, _, err := s.Run()
+	if err != nil {
+		t.Fatalf("Unexpected error %v", err)
+	}
+
+	if idx != 2 {
+		t.Errorf("Expected index 2, got %d", idx)
+	}
+
+	scriptedSelect(&s, "/w\x1b[B\r")
+
+	idx, _, err = s.Run()
+	if err != nil {
+		t.Fatalf("Unexpected error %v", err)
+	}
+
+	if idx != 1 {
+		t.Errorf("Expected index 1, got %d", idx)
+	}
+}
+
+func TestSelectChoose(t *testing.T) {
+	type pepper struct {
+		Name     string
+		HeatUnit int
+	}
+
+	s := Select{
+		Label: "Spicy Level",
+		Items: []pepper{{Name: "Bell Pepper", HeatUnit: 0}, {Name: "Poblano", HeatUnit: 1000}},
+	}
+
+	t.Run("when the index is valid", func(t *testing.T) {
+		idx, value, err := SelectChoose(s, 1)
+		if err != nil {
+			t.Fatalf("Unexpected error %v", err)
+		}
+
+		exp := "{Poblano 1000}"
+		if idx != 1 || value != exp {
+			t.Errorf("Expected (1, %q), got (%d, %q)", exp, idx, value)
+		}
+	})
+
+	t.Run("when the index is out of range", func(t *testing.T) {
+		for _, index := range []int{-1, 2} {
+			_, _, err := SelectChoose(s, index)
+			if err == nil {
+				t.Errorf("Expected error for index %d, got none", index)
+			}
+		}
+	})
+
+	t.Run("when the items are invalid", func(t *testing.T) {
+		_, _, err := SelectChoose(Select{Items: "Poblano"}, 0)
+		if err == nil {
+			t.Errorf("Expected error, got none")
+		}
+	})
+}
+
+func TestSelectAllowNone(t *testing.T) {
+	t.Run("when choosing none", func(t *testing.T) {
+		s := Select{
+			Label:     "Select Number",
+			Items:     []string{"Zero", "One"},
+			AllowNone: true,
+		}
+		scriptedSelect(&s, "\x1b[B\x18")
+
+		idx, value, err := s.Run()
+		if err != nil {
+			t.Fatalf("Unexpected error %v", err)
+		}
+
+		if idx != SelectedNone || value != "" {
+			t.Errorf("Expected (%d, %q), got (%d, %q)", SelectedNone, "", idx, value)
+		}
+	})
+
+	t.Run("when none is not allowed", func(t *testing.T) {
+		s := Select{
+			Label: "Select Number",
+			Items: []string{"Zero", "One"},
+		}
+		scriptedSelect(&s, "\x18\x1b[B\r")
+
+		idx, value, err := s.Run()
+		if err != nil {
+			t.Fatalf("Unexpected error %v", err)
+		}
+
+		if idx != 1 || value != "One" {
+			t.Errorf("Expected (1, %q), got (%d, %q)", "One", idx, value)
+		}
+	})
+}
+
+func TestSelectOnSelect(t *testing.T) {
+	t.Run("when the hook asks to reprompt", func(t *testing.T) {
+		var calls []int
+
+		s := Select{
+			Label: "Select Number",
+			Items: []string{"Zero", "One"},
+			OnSelect: func(index int) error {
+				calls = append(calls, index)
+				if len(calls) == 1 {
+					return ErrReprompt
+				}
+				return nil
+			},
+		}
+		scriptedSelect(&s, "\r\x1b[B\r")
+
+		idx, value, err := s.Run()
+		if err != nil {
+			t.Fatalf("Unexpected error %v", err)
+		}
+
+		if idx != 1 || value != "One" {
+			t.Errorf("Expected (1, %q), got (%d, %q)", "One", idx, value)
+		}
+
+		if len(calls) != 2 || calls[0] != 0 || calls[1] != 1 {
+			t.Errorf("Expected hook to be called with 0 then 1, got %v", calls)
+		}
+	})
+
+	t.Run("when the hook runs a prompt", func(t *testing.T) {
+		items := []string{"Zero", "One"}
+
+		// the first prompt renames the item, the second one keeps the new name
+		scripts := []string{"None\r", "\r"}
+
+		s := Select{
+			Label: "Select Number",
+			Items: items,
+			OnSelect: func(index int) error {
+				p := Prompt{
+					Label:   "Rename",
+					Default: items[index],
+					Stdin:   scriptedStdin(scripts[0]),
+					Stdout:  &nopWriteCloser{},
+				}
+				scripts = scripts[1:]
+
+				value, err := p.Run()
+				if err != nil {
+					return err
+				}
+
+				if value == items[index] {
+					return nil
+				}
+
+				items[index] = value
+				return ErrReprompt
+			},
+		}
+		scriptedSelect(&s, "\x1b[B\r\r")
+
+		idx, value, err := s.Run()
+		if err != nil {
+			t.Fatalf("Unexpected error %v", err)
+		}
+
+		if idx != 1 || value != "None" {
+			t.Errorf("Expected (1, %q), got (%d, %q)", "None", idx, value)
+		}
+	})
+
+	t.Run("when the hook fails", func(t *testing.T) {
+		expected := fmt.Errorf("unreachable")
+
+		s := Select{
+			Label: "Select Number",
+			Items: []string{"Zero", "One"},
+			OnSelect: func(index int) error {
+				return expected
+			},
+		}
+		scriptedSelect(&s, "\r")
+
+		_, _, err := s.Run()
+		if err != expected {
+			t.Errorf("Expected error %v, got %v", expected, err)
+		}
+	})
+}
+
+// inOrder checks that out contains the given parts, one after the other.
+func inOrder(t *testing.T, out string, parts ...string) {
+	t.Helper()
+	rest := out
+	for _, part := range parts {
+		i := strings.Index(rest, part)
+		if i < 0 {
+			t.Fatalf("Expected %q in order in %q", parts, out)
+		}
+		rest = rest[i+len(part):]
+	}
+}
+
+func TestSelectNestedPrompt(t *testing.T) {
+	t.Run("with a prompt", func(t *testing.T) {
+		var name string
+		s := Select{
+			Label:        "Select Number",
+			Items:        []string{"Zero", "One"},
+			MouseEnabled: true,
+		}
+		out := scriptedSelect(&s, "\x1b[B\r")
+		s.OnSelect = func(index int) error {
+			p := Prompt{Label: "Name", Stdin: scriptedStdin("jk\r"), Stdout: out}
+
+			var err error
+			name, err = p.Run()
+			return err
+		}
+
+		idx, _, err := s.Run()
+		if err != nil {
+			t.Fatalf("Unexpected error %v", err)
+		}
+
+		if idx != 1 || name != "jk" {
+			t.Errorf("Expected (1, %q), got (%d, %q)", "jk", idx, name)
+		}
+
+		// the mouse reporting of the select is off while the prompt runs, and back on once it is done
+		inOrder(t, out.String(), mouseOn, mouseOff, "Name", showCursor, hideCursor+mouseOn, mouseOff)
+	})
+
+	t.Run("with a select", func(t *testing.T) {
+		s := Select{
+			Label:        "Select Number",
+			Items:        []string{"Zero", "One"},
+			MouseEnabled: true,
+		}
+		out := scriptedSelect(&s, "\r\r")
+
+		calls := 0
+		s.OnSelect = func(index int) error {
+			calls++
+			if calls > 1 {
+				return nil
+			}
+
+			nested := Select{
+				Label:        "Select Letter",
+				Items:        []string{"a", "b"},
+				MouseEnabled: true,
+				Stdin:        scriptedStdin("\r"),
+				Stdout:       out,
+			}
+			if _, _, err := nested.Run(); err != nil {
+				return err
+			}
+			return ErrReprompt
+		}
+
+		if _, _, err := s.Run(); err != nil {
+			t.Fatalf("Unexpected error %v", err)
+		}
+
+		// the nested select turns the mouse reporting off once done, the outer one turns it back on
+		inOrder(t, out.String(), mouseOn, "Select Letter", mouseOff, hideCursor+mouseOn, "Select Number", mouseOff)
+		if before := strings.Split(out.String(), "Select Letter")[0]; strings.Contains(before, mouseOff) {
+			t.Errorf("Expected the mouse reporting to stay on for the nested select, got %q", before)
+		}
+	})
+}
+
+func TestSelectPanic(t *testing.T) {
+	s := Select{
+		Label: "Select Number",
+		Items: []string{"Zero", "One"},
+		OnSelect: func(index int) error {
+			panic("unreachable")
+		},
+	}
+	out := scriptedSelect(&s, "\r")
+
+	defer func() {
+		if r := recover(); r != "unreachable" {
+			t.Fatalf("Expected the panic of the hook, got %v", r)
+		}
+
+		if !strings.HasSuffix(out.String(), showCursor+mouseOff) {
+			t.Errorf("Expected the terminal to be restored, got %q", out.String())
+		}
+
+		if n := len(terminalStates.stack); n != 0 {
+			t.Errorf("Expected the terminal state to be popped, got %d states", n)
+		}
+	}()
+
+	s.Run()
+	t.Errorf("Expected Run to panic")
+}
+
+type recordedEvents struct {
+	events []string
+}
+
+func (r *recordedEvents) OnHighlight(index int) {
+	r.events = append(r.events, fmt.Sprintf("highlight %d", index))
+}
+
+func (r *recordedEvents) OnSearch(query string) {
+	r.events = append(r.events, fmt.Sprintf("search %q", query))
+}
+
+func (r *recordedEvents) OnSelect(index int) {
+	r.events = append(r.events, fmt.Sprintf("select %d", index))
+}
+
+func TestSelectEvents(t *testing.T) {
+	items := []string{"Zero", "One", "Two", "Three"}
+	events := &recordedEvents{}
+
+	s := Select{
+		Label:  "Select Number",
+		Items:  items,
+		Events: events,
+		Searcher: func(input string, index int) bool {
+			return strings.Contains(items[index], input)
+		},
+	}
+	scriptedSelect(&s, "\x1b[B/Tx\x7f\x1b[B\r")
+
+	_, _, err := s.Run()
+	if err != nil {
+		t.Fatalf("Unexpected error %v", err)
+	}
+
+	expected := []string{
+		"highlight 0",
+		"highlight 1",
+		`search "T"`,
+		"highlight 2",
+		`search "Tx"`,
+		"highlight -1",
+		`search "T"`,
+		"highlight 2",
+		"highlight 3",
+		"select 3",
+	}
+
+	if strings.Join(events.events, "\n") != strings.Join(expected, "\n") {
+		t.Errorf("Expected events %q, got %q", expected, events.events)
+	}
+}
+
+type planet int
+
+func (p planet) String() string {
+	return [...]string{"Mercury", "Venus", "Earth"}[p]
+}
+
+func TestSelectDefaultTemplates(t *testing.T) {
+	tcs := []struct {
+		scenario string
+		items    interface{}
+		display  string
+	}{
+		{scenario: "strings", items: []string{"Zero", "One"}, display: "One"},
+		{scenario: "ints", items: []int{0, 1}, display: "1"},
+		{scenario: "floats", items: []float64{0.5, 1.5}, display: "1.5"},
+		{scenario: "stringers", items: []planet{0, 1}, display: "Venus"},
+		{scenario: "mixed", items: []interface{}{"Zero", planet(1)}, display: "Venus"},
+	}
+
+	for _, tc := range tcs {
+		t.Run(tc.scenario, func(t *testing.T) {
+			s := Select{
+				Label: "Select",
+				Items: tc.items,
+			}
+			scriptedSelect(&s, "\x1b[B\r")
+
+			idx, value, err := s.Run()
+			if err != nil {
+				t.Fatalf("Unexpected error %v", err)
+			}
+
+			if idx != 1 || value != tc.display {
+				t.Errorf("Expected (1, %q), got (%d, %q)", tc.display, idx, value)
+			}
+
+			result := string(render(s.Templates.active, reflectIndex(tc.items, 1)))
+			exp := fmt.Sprintf("\x1b[1m▸\x1b[0m \x1b[4m%s\x1b[0m", tc.display)
+			if result != exp {
+				t.Errorf("Expected active item to eq %q, got %q", exp, result)
+			}
+
+			result = string(render(s.Templates.inactive, reflectIndex(tc.items, 1)))
+			exp = "  " + tc.display
+			if result != exp {
+				t.Errorf("Expected inactive item to eq %q, got %q", exp, result)
+			}
+
+			result = string(render(s.Templates.selected, reflectIndex(tc.items, 1)))
+			exp = fmt.Sprintf("\x1b[32m\x1b[32m✔\x1b[0m \x1b[2m%s\x1b[0m", tc.display)
+			if result != exp {
+				t.Errorf("Expected selected item to eq %q, got %q", exp, result)
+			}
+		})
+	}
+}
+
+func reflectIndex(items interface{}, i int) interface{} {
+	return reflect.ValueOf(items).Index(i).Interface()
+}
+
+func TestSelectLabelCounts(t *testing.T) {
+	type label struct {
+		Name string
+	}
+
+	items := []string{"web-1", "web-2", "db-1"}
+
+	s := Select{
+		Label: label{Name: "Pick a server"},
+		Items: items,
+		Templates: &SelectTemplates{
+			Label: "{{ .Name }} ({{ filtered }}/{{ total }} available)",
+		},
+		Searcher: func(input string, index int) bool {
+			return strings.HasPrefix(items[index], input)
+		},
+	}
+
+	out := scriptedSelect(&s, "/web\r")
+
+	_, _, err := s.Run()
+	if err != nil {
+		t.Fatalf("Unexpected error %v", err)
+	}
+
+	for _, exp := range []string{"Pick a server (3/3 available)", "Pick a server (2/3 available)"} {
+		if !strings.Contains(out.String(), exp) {
+			t.Errorf("Expected output to contain %q, got %q", exp, out.String())
+		}
+	}
+
+	result := string(render(s.Templates.label, s.Label))
+	exp := "Pick a server (2/3 available)"
+	if result != exp {
+		t.Errorf("Expected label to eq %q, got %q", exp, result)
+	}
+}
+
+func TestSelectTemplateData(t *testing.T) {
+	type server struct {
+		Name string
+		User string
+	}
+
+	s := Select{
+		Label: "Connect to",
+		Items: []server{{Name: "web-1", User: "deploy"}},
+		Templates: &SelectTemplates{
+			Active:   `{{ .User }}@{{ .Name }} as {{ data "User" }} on {{ data "Env" }}`,
+			Inactive: "{{ .Name }}",
+			Selected: `{{ .Name }} ({{ data "Env" }})`,
+		},
+		TemplateData: map[string]interface{}{"User": "alice", "Env": "staging"},
+	}
+
+	out := scriptedSelect(&s, "\r")
+
+	_, _, err := s.Run()
+	if err != nil {
+		t.Fatalf("Unexpected error %v", err)
+	}
+
+	for _, exp := range []string{"deploy@web-1 as alice on staging", "web-1 (staging)"} {
+		if !strings.Contains(out.String(), exp) {
+			t.Errorf("Expected output to contain %q, got %q", exp, out.String())
+		}
+	}
+}
+
+func TestSelectAnnotations(t *testing.T) {
+	items := []string{"us-east-1", "eu-west-1", "ap-southeast-2"}
+	annotations := map[int]string{0: "[default]", 2: "(deprecated)"}
+
+	faint := Styler(FGFaint)
+
+	s := Select{
+		Label: "Region",
+		Items: items,
+		AnnotationFunc: func(index int) string {
+			return annotations[index]
+		},
+		Searcher: func(input string, index int) bool {
+			return strings.HasPrefix(items[index], input)
+		},
+	}
+	out := scriptedSelect(&s, "/ap\r")
+
+	_, _, err := s.Run()
+	if err != nil {
+		t.Fatalf("Unexpected error %v", err)
+	}
+
+	// the annotations end in the same column, past the widest item
+	for _, exp := range []string{
+		Styler(FGUnderline)("us-east-1") + "         " + faint("[default]") + "\n",
+		"    eu-west-1\n",
+		"    ap-southeast-2 " + faint("(deprecated)") + "\n",
+	} {
+		if !strings.Contains(out.String(), exp) {
+			t.Errorf("Expected output to contain %q, got %q", exp, out.String())
+		}
+	}
+
+	// the annotations follow the items matching the search
+	if exp := Styler(FGUnderline)("ap-southeast-2") + " " + faint("(deprecated)"); !strings.Contains(out.String(), exp) {
+		t.Errorf("Expected output to contain %q, got %q", exp, out.String())
+	}
+}
+
+func TestSelectLayout(t *testing.T) {
+	type pepper struct {
+		Name string
+		Heat string
+	}
+
+	s := Select{
+		Label: "Pepper",
+		Items: []pepper{{"Bell", "mild"}, {"Jalapeño", "medium"}, {"Habanero", "hot"}, {"Ghost", "very hot"}},
+		Size:  4,
+		Templates: &SelectTemplates{
+			Label:    `{{ . }} ({{ if detailed }}detailed{{ else }}compact{{ end }})`,
+			Active:   "> {{ .Name }}",
+			Inactive: "  {{ .Name }}",
+			Selected: "{{ .Name }}",
+			Details:  "    {{ .Heat }}",
+		},
+	}
+	out := scriptedSelect(&s, "\x14jj\r")
+
+	idx, _, err := s.Run()
+	if err != nil {
+		t.Fatalf("Unexpected error %v", err)
+	}
+
+	if idx != 2 {
+		t.Errorf("Expected index 2, got %d", idx)
+	}
+
+	if s.Layout != LayoutDetailed {
+		t.Errorf("Expected the detailed layout once toggled, got %d", s.Layout)
+	}
+
+	// the first frame ends its lines with newlines, the next ones move down over the lines already there
+	line := func(text string) string {
+		return clearLine + "\r" + text + "\n"
+	}
+	redrawn := func(text string) string {
+		return clearLine + "\r" + text + esc + "1B"
+	}
+
+	for _, exp := range []string{
+		Styler(FGFaint)("^T") + " " + Styler(FGFaint)("toggles details"),
+		// the compact layout shows the details of the active item below the list
+		line("Pepper (compact)") + line("  > Bell") + line("    Jalapeño") + line("    Habanero") + line("    Ghost") +
+			line("    mild"),
+		// the detailed layout shows the details of each item, on pages of half the size
+		redrawn("Pepper (detailed)") + redrawn("  > Bell") + redrawn("    mild") + redrawn("↓   Jalapeño") +
+			redrawn("    medium") + redrawn(""),
+		redrawn("Pepper (detailed)") + redrawn("↑   Jalapeño") + redrawn("    medium") + redrawn("↓ > Habanero") +
+			redrawn("    hot") + redrawn(""),
+	} {
+		if !strings.Contains(out.String(), exp) {
+			t.Errorf("Expected output to contain %q, got %q", exp, out.String())
+		}
+	}
+}
+
+func TestSelectTags(t *testing.T) {
+	type pkg struct {
+		Name string
+		Tags []string
+	}
+
+	pkgs := []pkg{
+		{"curl", []string{"net"}},
+		{"gzip", []string{"archive"}},
+		{"wget", []string{"net"}},
+		{"bzip2", []string{"archive", "legacy"}},
+	}
+
+	tcs := []struct {
+		scenario string
+		keys     string
+		expect   int
+	}{
+		{scenario: "filter by the first tag", keys: "\tj\r", expect: 2},
+		{scenario: "filter by the next tag", keys: "\t\tj\r", expect: 3},
+		{scenario: "cycle back to all the items", keys: "\t\t\t\tk\r", expect: 2},
+		{scenario: "search along with the tag", keys: "\t\t/b\r", expect: 3},
+		{scenario: "keep the tag once the search is cancelled", keys: "\t/w/k\r", expect: 0},
+	}
+
+	for _, tc := range tcs {
+		t.Run(tc.scenario, func(t *testing.T) {
+			s := Select{
+				Label: "Package",
+				Items: pkgs,
+				TagFunc: func(index int) []string {
+					return pkgs[index].Tags
+				},
+				Searcher: func(input string, index int) bool {
+					return strings.HasPrefix(pkgs[index].Name, input)
+				},
+			}
+			scriptedSelect(&s, tc.keys)
+
+			idx, _, err := s.Run()
+			if err != nil {
+				t.Fatalf("Unexpected error %v", err)
+			}
+
+			if idx != tc.expect {
+				t.Errorf("Expected index %d, got %d", tc.expect, idx)
+			}
+		})
+	}
+
+	t.Run("when displaying the tag", func(t *testing.T) {
+		s := Select{
+			Label: "Package",
+			Items: pkgs,
+			TagFunc: func(index int) []string {
+				return pkgs[index].Tags
+			},
+			Searcher: func(input string, index int) bool {
+				return strings.HasPrefix(pkgs[index].Name, input)
+			},
+		}
+		out := scriptedSelect(&s, "\t/\r")
+
+		_, _, err := s.Run()
+		if err != nil {
+			t.Fatalf("Unexpected error %v", err)
+		}
+
+		tag := Styler(FGFaint)("tag:") + " " + Styler(FGCyan)("net")
+		for _, exp := range []string{
+			Styler(FGFaint)("⇥") + " " + Styler(FGFaint)("filters by tag"),
+			Styler(FGFaint)("filters by tag") + "  " + tag,
+			SearchPrompt + "█  " + tag,
+		} {
+			if !strings.Contains(out.String(), exp) {
+				t.Errorf("Expected output to contain %q, got %q", exp, out.String())
+			}
+		}
+	})
+}
+
+func TestSelectReprompt(t *testing.T) {
+	calls := 0
+
+	s := Select{
+		Label: "Select Server",
+		Items: []string{"web", "db"},
+		OnSelect: func(index int) error {
+			calls++
+			if index == 0 {
+				return Reprompt(fmt.Errorf("server unreachable"))
+			}
+			return nil
+		},
+	}
+
+	out := scriptedSelect(&s, "\r\x1b[B\r")
+
+	idx, _, err := s.Run()
+	if err != nil {
+		t.Fatalf("Unexpected error %v", err)
+	}
+
+	if idx != 1 || calls != 2 {
+		t.Errorf("Expected index 1 after 2 calls, got %d after %d calls", idx, calls)
+	}
+
+	exp := "\x1b[31m>>\x1b[0m \x1b[31mserver unreachable\x1b[0m"
+	if n := strings.Count(out.String(), exp); n != 1 {
+		t.Errorf("Expected the error to be displayed once, got %d times in %q", n, out.String())
+	}
+}
+
+func TestSelectDefaultKeys(t *testing.T) {
+	keys := DefaultSelectKeys
+	keys.Next = Key{Code: 'n', Display: "n"}
+
+	s := Select{
+		Label: "Select Number",
+		Items: []string{"Zero", "One", "Two"},
+		Keys:  &keys,
+	}
+	out := scriptedSelect(&s, "nn\x1b[A\r")
+
+	idx, _, err := s.Run()
+	if err != nil {
+		t.Fatalf("Unexpected error %v", err)
+	}
+
+	if idx != 1 {
+		t.Errorf("Expected index 1, got %d", idx)
+	}
+
+	if !strings.Contains(out.String(), "\x1b[2mn\x1b[0m \x1b[2m↑\x1b[0m") {
+		t.Errorf("Expected the help to display the new key, got %q", out.String())
+	}
+
+	if DefaultSelectKeys.Next.Code != KeyNext {
+		t.Errorf("Expected DefaultSelectKeys to be left unchanged")
+	}
+
+	s = Select{Items: []string{"Zero"}}
+	s.setKeys()
+
+	if *s.Keys != DefaultSelectKeys {
+		t.Errorf("Expected %v, got %v", DefaultSelectKeys, *s.Keys)
+	}
+}
+
+func TestSelectAlternateKeys(t *testing.T) {
+	keys := DefaultSelectKeys
+	keys.Next.Alternate = &Key{Code: 'n', Display: "n", Alternate: &Key{Code: 15}}
+
+	s := Select{
+		Label: "Select Number",
+		Items: []string{"Zero", "One", "Two"},
+		Keys:  &keys,
+	}
+	out := scriptedSelect(&s, "n\x0f\r")
+
+	idx, _, err := s.Run()
+	if err != nil {
+		t.Fatalf("Unexpected error %v", err)
+	}
+
+	if idx != 2 {
+		t.Errorf("Expected index 2, got %d", idx)
+	}
+
+	if !strings.Contains(out.String(), "↓/n") {
+		t.Errorf("Expected the help to display all the keys, got %q", out.String())
+	}
+}
+
+func TestSelectConfirmKey(t *testing.T) {
+	items := []string{"Zero", "One", "Two", "Three"}
+
+	t.Run("when searching", func(t *testing.T) {
+		keys := DefaultSelectKeys
+		keys.Confirm = Key{Code: KeyPaste, Display: "^Y"}
+
+		s := Select{
+			Label: "Select Number",
+			Items: items,
+			Keys:  &keys,
+			Searcher: func(input string, index int) bool {
+				return strings.HasPrefix(strings.ToLower(items[index]), input)
+			},
+			StartInSearchMode: true,
+		}
+		scriptedSelect(&s, "t\rh\x19")
+
+		idx, _, err := s.Run()
+		if err != nil {
+			t.Fatalf("Unexpected error %v", err)
+		}
+
+		if idx != 3 {
+			t.Errorf("Expected index 3, got %d", idx)
+		}
+	})
+
+	t.Run("when displaying the help", func(t *testing.T) {
+		keys := DefaultSelectKeys
+		keys.Confirm = Key{Code: KeyPaste, Display: "^Y"}
+
+		s := Select{Label: "Select Number", Items: items, Keys: &keys}
+		out := scriptedSelect(&s, "j\rj\x19")
+
+		idx, _, err := s.Run()
+		if err != nil {
+			t.Fatalf("Unexpected error %v", err)
+		}
+
+		if idx != 2 {
+			t.Errorf("Expected index 2, got %d", idx)
+		}
+
+		if exp := Styler(FGFaint)("^Y") + " " + Styler(FGFaint)("confirms"); !strings.Contains(out.String(), exp) {
+			t.Errorf("Expected the help to display the confirm key, got %q", out.String())
+		}
+	})
+
+	t.Run("when confirming with a line feed", func(t *testing.T) {
+		s := Select{Label: "Select Number", Items: items}
+		scriptedSelect(&s, "j\n")
+
+		idx, value, err := s.Run()
+		if err != nil {
+			t.Fatalf("Unexpected error %v", err)
+		}
+
+		if idx != 1 || value != "One" {
+			t.Errorf("Expected index 1 and One, got %d and %q", idx, value)
+		}
+	})
+
+	t.Run("when ignoring a line feed", func(t *testing.T) {
+		keys := DefaultSelectKeys
+		keys.Confirm = Key{Code: KeyPaste, Display: "^Y"}
+
+		s := Select{Label: "Select Number", Items: items, Keys: &keys}
+		scriptedSelect(&s, "j\nj\x19")
+
+		idx, _, err := s.Run()
+		if err != nil {
+			t.Fatalf("Unexpected error %v", err)
+		}
+
+		if idx != 2 {
+			t.Errorf("Expected index 2, got %d", idx)
+		}
+	})
+
+	t.Run("when enter is an alternate", func(t *testing.T) {
+		keys := DefaultSelectKeys
+		keys.Confirm = Key{Code: KeyPaste, Display: "^Y", Alternate: &Key{Code: KeyEnter}}
+
+		s := Select{Label: "Select Number", Items: items, Keys: &keys}
+		scriptedSelect(&s, "jj\r")
+
+		idx, _, err := s.Run()
+		if err != nil {
+			t.Fatalf("Unexpected error %v", err)
+		}
+
+		if idx != 2 {
+			t.Errorf("Expected index 2, got %d", idx)
+		}
+	})
+
+	t.Run("when the keys don't set it", func(t *testing.T) {
+		keys := DefaultSelectKeys
+		keys.Confirm = Key{}
+
+		s := Select{Label: "Select Number", Items: items, Keys: &keys}
+		out := scriptedSelect(&s, "j\r")
+
+		idx, _, err := s.Run()
+		if err != nil {
+			t.Fatalf("Unexpected error %v", err)
+		}
+
+		if idx != 1 {
+			t.Errorf("Expected index 1, got %d", idx)
+		}
+
+		if strings.Contains(out.String(), "confirms") {
+			t.Errorf("Expected the help not to display enter, got %q", out.String())
+		}
+	})
+}
+
+func TestSelectSearchSpaces(t *testing.T) {
+	items := []string{"bar", "afoo", "a foo", "foo"}
+	searcher := func(input string, index int) bool {
+		return strings.HasPrefix(items[index], input)
+	}
+
+	t.Run("when trimmed", func(t *testing.T) {
+		s := Select{Label: "Item", Items: items, Searcher: searcher}
+		scriptedSelect(&s, "/\t foo \r")
+
+		idx, value, err := s.Run()
+		if err != nil {
+			t.Fatalf("Unexpected error %v", err)
+		}
+
+		if idx != 3 || value != "foo" {
+			t.Errorf("Expected (3, %q), got (%d, %q)", "foo", idx, value)
+		}
+	})
+
+	t.Run("when kept", func(t *testing.T) {
+		s := Select{Label: "Item", Items: items, Searcher: searcher, KeepSearchSpaces: true}
+		scriptedSelect(&s, "/a \r")
+
+		idx, value, err := s.Run()
+		if err != nil {
+			t.Fatalf("Unexpected error %v", err)
+		}
+
+		if idx != 2 || value != "a foo" {
+			t.Errorf("Expected (2, %q), got (%d, %q)", "a foo", idx, value)
+		}
+	})
+}
+
+func TestSelectScoredSearcher(t *testing.T) {
+	items := []string{"bar", "foo", "baz"}
+	searcher := func(query []rune, index int) (list.Match, bool) {
+		i := strings.Index(items[index], string(query))
+		if i < 0 {
+			return list.Match{}, false
+		}
+		return list.Match{Ranges: []list.Range{{Start: i, End: i + len(query)}}}, true
+	}
+
+	s := Select{
+		Label:          "Item",
+		Items:          items,
+		ScoredSearcher: searcher,
+		Templates: &SelectTemplates{
+			Active:   "> {{ highlight . }}",
+			Inactive: "  {{ highlight . }}",
+		},
+	}
+	out := scriptedSelect(&s, "/a\x1b[B\r")
+
+	idx, value, err := s.Run()
+	if err != nil {
+		t.Fatalf("Unexpected error %v", err)
+	}
+
+	if idx != 2 || value != "baz" {
+		t.Errorf("Expected (2, %q), got (%d, %q)", "baz", idx, value)
+	}
+
+	for _, exp := range []string{"> b\x1b[1ma\x1b[22mr", "  b\x1b[1ma\x1b[22mz", "  foo"} {
+		if !strings.Contains(out.String(), exp) {
+			t.Errorf("Expected output to contain %q, got %q", exp, out.String())
+		}
+	}
+}
+
+func TestSelectSortByScore(t *testing.T) {
+	items := []string{"banana", "apple", "grape"}
+	searcher := func(query []rune, index int) (list.Match, bool) {
+		i := strings.Index(items[index], string(query))
+		if i < 0 {
+			return list.Match{}, false
+		}
+		return list.Match{Score: -i}, true
+	}
+
+	s := Select{Label: "Item", Items: items, ScoredSearcher: searcher, SortByScore: true}
+	scriptedSelect(&s, "/a\x1b[B\x1b[Bp\r")
+
+	idx, value, err := s.Run()
+	if err != nil {
+		t.Fatalf("Unexpected error %v", err)
+	}
+
+	if idx != 1 || value != "apple" {
+		t.Errorf("Expected (1, %q), got (%d, %q)", "apple", idx, value)
+	}
+}
+
+func TestSelectNoResults(t *testing.T) {
+	items := []string{"bar", "foo"}
+	searcher := func(input string, index int) bool {
+		return strings.Contains(items[index], input)
+	}
+
+	t.Run("with the default message", func(t *testing.T) {
+		s := Select{Label: "Item", Items: items, Searcher: searcher}
+		out := scriptedSelect(&s, "/xyz\r\b\b\bfo\r")
+
+		idx, _, err := s.Run()
+		if err != nil {
+			t.Fatalf("Unexpected error %v", err)
+		}
+
+		if idx != 1 {
+			t.Errorf("Expected the search to recover and select 1, got %d", idx)
+		}
+
+		exp := `No results for "xyz"`
+		if !strings.Contains(out.String(), exp) {
+			t.Errorf("Expected output to contain %q, got %q", exp, out.String())
+		}
+	})
+
+	t.Run("with a custom template", func(t *testing.T) {
+		s := Select{
+			Label:    "Item",
+			Items:    items,
+			Searcher: searcher,
+			Templates: &SelectTemplates{
+				NoResults: "Nothing matches {{ . }}\nTry another search",
+			},
+		}
+		out := scriptedSelect(&s, "/xyz\x03")
+
+		_, _, err := s.Run()
+		if err != ErrInterrupt {
+			t.Fatalf("Expected %v, got %v", ErrInterrupt, err)
+		}
+
+		for _, exp := range []string{"Nothing matches xyz", "Try another search"} {
+			if !strings.Contains(out.String(), exp) {
+				t.Errorf("Expected output to contain %q, got %q", exp, out.String())
+			}
+		}
+	})
+}
+
+func TestSelectAutoSize(t *testing.T) {
+	withHeight := func(height int, ok bool) func() {
+		orig := terminalHeight
+		terminalHeight = func(io.Writer) (int, bool) {
+			return height, ok
+		}
+		return func() { terminalHeight = orig }
+	}
+
+	items := make([]string, 20)
+	for i := range items {
+		items[i] = fmt.Sprintf("item %d", i+1)
+	}
+
+	cases := []struct {
+		name    string
+		height  int
+		ok      bool
+		details string
+		size    int
+	}{
+		{name: "with the help", height: 10, ok: true, size: 7},
+		{name: "with details", height: 10, ok: true, details: "\n---\n{{ . }}", size: 4},
+		{name: "on a tiny terminal", height: 2, ok: true, size: 1},
+		{name: "without a terminal", ok: false, size: 3},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			defer withHeight(tc.height, tc.ok)()
+
+			s := Select{
+				Label:     "Item",
+				Items:     items,
+				Size:      3,
+				AutoSize:  true,
+				Templates: &SelectTemplates{Details: tc.details},
+			}
+			scriptedSelect(&s, "\r")
+
+			_, _, err := s.Run()
+			if err != nil {
+				t.Fatalf("Unexpected error %v", err)
+			}
+
+			if s.size != tc.size {
+				t.Errorf("Expected a size of %d, got %d", tc.size, s.size)
+			}
+
+			if s.Size != 3 {
+				t.Errorf("Expected the Size to be left at 3, got %d", s.Size)
+			}
+		})
+	}
+}
+
+func TestSelectActivePointer(t *testing.T) {
+	frames := []string{"▸", "▹"}
+	var calls int
+	animated := func(prefix []rune) []rune {
+		frame := frames[calls%len(frames)]
+		calls++
+		return []rune(frame)
+	}
+
+	s := Select{
+		Label: "Item",
+		Items: []string{"a", "b"},
+		Templates: &SelectTemplates{
+			ActivePointer: animated,
+		},
+	}
+	out := scriptedSelect(&s, "j\r")
+
+	_, _, err := s.Run()
+	if err != nil {
+		t.Fatalf("Unexpected error %v", err)
+	}
+
+	for _, exp := range []string{"▸ \x1b[4ma\x1b[0m", "▹ \x1b[4mb\x1b[0m"} {
+		if !strings.Contains(out.String(), exp) {
+			t.Errorf("Expected output to contain %q, got %q", exp, out.String())
+		}
+	}
+}
+
+func TestSelectRedrawMode(t *testing.T) {
+	run := func(mode screenbuf.RedrawMode) string {
+		s := Select{
+			Label:      "Pick an item",
+			Items:      []string{"a", "b", "c"},
+			RedrawMode: mode,
+		}
+		out := scriptedSelect(&s, "jj\r")
+
+		_, result, err := s.Run()
+		if err != nil {
+			t.Fatalf("Unexpected error %v", err)
+		}
+
+		if result != "c" {
+			t.Errorf("Expected %q, got %q", "c", result)
+		}
+		return out.String()
+	}
+
+	full := strings.Count(run(screenbuf.Full), "Pick an item")
+	incremental := strings.Count(run(screenbuf.Incremental), "Pick an item")
+
+	if incremental >= full {
+		t.Errorf("Expected the label to be drawn less than %d times, got %d", full, incremental)
+	}
+}
+
+// writeCounter counts the writes made to it.
+type writeCounter struct {
+	nopWriteCloser
+	writes int
+}
+
+func (w *writeCounter) Write(b []byte) (int, error) {
+	w.writes++
+	return w.nopWriteCloser.Write(b)
+}
+
+func TestSelectWritesPerRedraw(t *testing.T) {
+	s := Select{
+		Label: "Pick an item",
+		Items: []string{"a", "b", "c"},
+	}
+	scriptedSelect(&s, "jj\r")
+	out := &writeCounter{}
+	s.Stdout = out
+
+	_, _, err := s.Run()
+	if err != nil {
+		t.Fatalf("Unexpected error %v", err)
+	}
+
+	// hiding the cursor, then the initial list, the two moves and the selected item
+	if out.writes != 5 {
+		t.Errorf("Expected 5 writes, got %d", out.writes)
+	}
+}
+
+func BenchmarkSelectRedraw(b *testing.B) {
+	moves := strings.Repeat("j", 20)
+	items := make([]string, 50)
+	for i := range items {
+		items[i] = fmt.Sprintf("item %d", i)
+	}
+
+	var writes, redraws int
+	for i := 0; i < b.N; i++ {
+		s := Select{
+			Label: "Pick an item",
+			Items: items,
+		}
+		scriptedSelect(&s, moves+"\r")
+		out := &writeCounter{}
+		s.Stdout = out
+
+		_, _, err := s.Run()
+		if err != nil {
+			b.Fatalf("Unexpected error %v", err)
+		}
+
+		// the cursor is hidden before the first redraw
+		writes += out.writes - 1
+		redraws += len(moves) + 2
+	}
+
+	b.Logf("%.2f writes per redraw", float64(writes)/float64(redraws))
+}
+
+// highlights calls its function each time an item is highlighted.
+type highlights func(index int)
+
+func (h highlights) OnHighlight(index int) { h(index) }
+func (highlights) OnSearch(string)         {}
+func (highlights) OnSelect(int)            {}
+
+// waitReader blocks until ready is closed, or for a second at most, then reads from r.
+type waitReader struct {
+	ready <-chan struct{}
+	r     io.Reader
+}
+
+func (w waitReader) Read(p []byte) (int, error) {
+	select {
+	case <-w.ready:
+	case <-time.After(time.Second):
+	}
+	return w.r.Read(p)
+}
+
+func TestSelectUpdates(t *testing.T) {
+	updates := make(chan []interface{}, 1)
+	ready := make(chan struct{})
+
+	s := Select{
+		Label:   "Job",
+		Items:   []interface{}{"a", "b"},
+		Updates: updates,
+		Events: highlights(func(index int) {
+			switch index {
+			case 0:
+				// the initial highlight of "a", which moves down once "x" is added before it
+				updates <- []interface{}{"x", "a", "b"}
+			case 1:
+				close(updates)
+				close(ready)
+			}
+		}),
+	}
+	scriptedSelect(&s, "")
+	s.Stdin = ioutil.NopCloser(waitReader{ready: ready, r: scriptedStdin("j\r")})
+
+	idx, result, err := s.Run()
+	if err != nil {
+		t.Fatalf("Unexpected error %v", err)
+	}
+
+	if idx != 2 || result != "b" {
+		t.Errorf("Expected item 2 %q, got %d %q", "b", idx, result)
+	}
+}
+
+func TestSelectOnDelete(t *testing.T) {
+	t.Run("removes the item", func(t *testing.T) {
+		var deleted []int
+		s := Select{
+			Label: "Connection",
+			Items: []string{"a", "b", "c"},
+			OnDelete: func(index int) error {
+				deleted = append(deleted, index)
+				return nil
+			},
+		}
+		out := scriptedSelect(&s, "j\x04\r")
+
+		idx, value, err := s.Run()
+		if err != nil {
+			t.Fatalf("Unexpected error %v", err)
+		}
+
+		if idx != 1 || value != "c" {
+			t.Errorf("Expected (1, %q), got (%d, %q)", "c", idx, value)
+		}
+
+		if !reflect.DeepEqual(deleted, []int{1}) {
+			t.Errorf("Expected item 1 to be deleted, got %v", deleted)
+		}
+
+		if !reflect.DeepEqual(s.Items, []string{"a", "c"}) {
+			t.Errorf("Expected the item to be removed, got %v", s.Items)
+		}
+
+		if !strings.Contains(out.String(), "deletes") {
+			t.Errorf("Expected the help to show the delete key, got %q", out.String())
+		}
+	})
+
+	t.Run("keeps the item on error", func(t *testing.T) {
+		s := Select{
+			Label: "Connection",
+			Items: []string{"a", "b"},
+			OnDelete: func(index int) error {
+				return fmt.Errorf("in use")
+			},
+		}
+		out := scriptedSelect(&s, "\x04\r")
+
+		idx, value, err := s.Run()
+		if err != nil {
+			t.Fatalf("Unexpected error %v", err)
+		}
+
+		if idx != 0 || value != "a" {
+			t.Errorf("Expected (0, %q), got (%d, %q)", "a", idx, value)
+		}
+
+		if !strings.Contains(out.String(), "in use") {
+			t.Errorf("Expected the error to be displayed, got %q", out.String())
+		}
+	})
+
+	t.Run("asks for confirmation", func(t *testing.T) {
+		var deleted []int
+		s := Select{
+			Label:                "Connection",
+			Items:                []string{"a", "b"},
+			RequireDeleteConfirm: true,
+			OnDelete: func(index int) error {
+				deleted = append(deleted, index)
+				return nil
+			},
+		}
+		out := scriptedSelect(&s, "")
+		s.Stdin = ioutil.NopCloser(io.MultiReader(iotest.OneByteReader(strings.NewReader("\x04n\r\x04y\r\r")), idleReader{}))
+
+		idx, value, err := s.Run()
+		if err != nil {
+			t.Fatalf("Unexpected error %v", err)
+		}
+
+		if idx != 0 || value != "b" {
+			t.Errorf("Expected (0, %q), got (%d, %q)", "b", idx, value)
+		}
+
+		if !reflect.DeepEqual(deleted, []int{0}) {
+			t.Errorf("Expected item 0 to be deleted once, got %v", deleted)
+		}
+
+		if !strings.Contains(out.String(), "Delete a") {
+			t.Errorf("Expected the confirm prompt, got %q", out.String())
+		}
+	})
+
+	t.Run("asks for confirmation with the keys read at once", func(t *testing.T) {
+		var deleted []int
+		s := Select{
+			Label:                "Connection",
+			Items:                []string{"a", "b"},
+			RequireDeleteConfirm: true,
+			OnDelete: func(index int) error {
+				deleted = append(deleted, index)
+				return nil
+			},
+		}
+		scriptedSelect(&s, "\x04n\r\x04y\r\r")
+
+		idx, value, err := s.Run()
+		if err != nil {
+			t.Fatalf("Unexpected error %v", err)
+		}
+
+		if idx != 0 || value != "b" {
+			t.Errorf("Expected (0, %q), got (%d, %q)", "b", idx, value)
+		}
+
+		if !reflect.DeepEqual(deleted, []int{0}) {
+			t.Errorf("Expected item 0 to be deleted once, got %v", deleted)
+		}
+	})
+
+	t.Run("ends on an interrupt of the confirmation", func(t *testing.T) {
+		s := Select{
+			Label:                "Connection",
+			Items:                []string{"a", "b"},
+			RequireDeleteConfirm: true,
+			OnDelete: func(index int) error {
+				t.Errorf("Expected no item to be deleted, got %d", index)
+				return nil
+			},
+		}
+		scriptedSelect(&s, "\x04\x03\r")
+
+		_, _, err := s.Run()
+		if err != ErrInterrupt {
+			t.Errorf("Expected ErrInterrupt, got %v", err)
+		}
+	})
+
+	t.Run("with another delete key", func(t *testing.T) {
+		// the keys typed after the delete key are read at once, which is meant to be run with the race detector
+		keys := DefaultSelectKeys
+		keys.Delete = Key{Code: 'x', Display: "x"}
+
+		var deleted []int
+		s := Select{
+			Label: "Connection",
+			Items: []string{"a", "b", "c", "d", "e"},
+			Keys:  &keys,
+			OnDelete: func(index int) error {
+				deleted = append(deleted, index)
+				return nil
+			},
+		}
+		scriptedSelect(&s, "xjxjxj\r")
+
+		idx, value, err := s.Run()
+		if err != nil {
+			t.Fatalf("Unexpected error %v", err)
+		}
+
+		if idx != 1 || value != "d" {
+			t.Errorf("Expected (1, %q), got (%d, %q)", "d", idx, value)
+		}
+
+		if !reflect.DeepEqual(deleted, []int{0, 1, 2}) {
+			t.Errorf("Expected the items 0, 1 and 2 in turn to be deleted, got %v", deleted)
+		}
+
+		if !reflect.DeepEqual(s.Items, []string{"b", "d"}) {
+			t.Errorf("Expected the items b and d to be left, got %v", s.Items)
+		}
+	})
+}
+
+func TestSelectOnReorder(t *testing.T) {
+	t.Run("moves the item", func(t *testing.T) {
+		var moves [][2]int
+		s := Select{
+			Label: "Rule",
+			Items: []string{"low", "mid", "high"},
+			OnReorder: func(from, to int) error {
+				moves = append(moves, [2]int{from, to})
+				return nil
+			},
+		}
+		out := scriptedSelect(&s, "jj\x1b[1;3A\x1b\x1b[A\x1b[1;3A\r")
+
+		idx, value, err := s.Run()
+		if err != nil {
+			t.Fatalf("Unexpected error %v", err)
+		}
+
+		if idx != 0 || value != "high" {
+			t.Errorf("Expected (0, %q), got (%d, %q)", "high", idx, value)
+		}
+
+		if exp := [][2]int{{2, 1}, {1, 0}}; !reflect.DeepEqual(moves, exp) {
+			t.Errorf("Expected the moves %v, got %v", exp, moves)
+		}
+
+		if exp := []string{"high", "low", "mid"}; !reflect.DeepEqual(s.Items, exp) {
+			t.Errorf("Expected the items in order %v, got %v", exp, s.Items)
+		}
+
+		if !strings.Contains(out.String(), "moves") {
+			t.Errorf("Expected the help to show the move keys, got %q", out.String())
+		}
+	})
+
+	t.Run("keeps the order on error", func(t *testing.T) {
+		s := Select{
+			Label: "Rule",
+			Items: []string{"low", "mid"},
+			OnReorder: func(from, to int) error {
+				return fmt.Errorf("locked")
+			},
+		}
+		out := scriptedSelect(&s, "\x1b[1;3B\r")
+
+		idx, value, err := s.Run()
+		if err != nil {
+			t.Fatalf("Unexpected error %v", err)
+		}
+
+		if idx != 0 || value != "low" {
+			t.Errorf("Expected (0, %q), got (%d, %q)", "low", idx, value)
+		}
+
+		if !strings.Contains(out.String(), "locked") {
+			t.Errorf("Expected the error to be displayed, got %q", out.String())
+		}
+	})
+
+	t.Run("not while searching", func(t *testing.T) {
+		items := []string{"low", "mid", "high"}
+		s := Select{
+			Label: "Rule",
+			Items: items,
+			Searcher: func(input string, index int) bool {
+				return strings.Contains(items[index], input)
+			},
+			OnReorder: func(from, to int) error {
+				t.Errorf("Unexpected move from %d to %d", from, to)
+				return nil
+			},
+		}
+		scriptedSelect(&s, "/i\x1b[1;3B\r")
+
+		idx, value, err := s.Run()
+		if err != nil {
+			t.Fatalf("Unexpected error %v", err)
+		}
+
+		if idx != 1 || value != "mid" {
+			t.Errorf("Expected (1, %q), got (%d, %q)", "mid", idx, value)
+		}
+	})
+}
+
+func TestRenderSelectPreview(t *testing.T) {
+	t.Run("renders the first frame", func(t *testing.T) {
+		out := &nopWriteCloser{}
+		s := Select{
+			Label:    "Pick",
+			Items:    []string{"a", "b", "c"},
+			Size:     2,
+			HideHelp: true,
+			Templates: &SelectTemplates{
+				Label:    "{{ . }}:",
+				Active:   "> {{ . }}",
+				Inactive: "  {{ . }}",
+				Details:  "selected {{ . }}",
+			},
+			Stdout: out,
+		}
+
+		preview, err := RenderSelectPreview(s)
+		if err != nil {
+			t.Fatalf("Unexpected error %v", err)
+		}
+
+		exp := "Pick:\n  > a\n↓   b\nselected a\n"
+		if preview != exp {
+			t.Errorf("Expected %q, got %q", exp, preview)
+		}
+
+		if out.Len() != 0 {
+			t.Errorf("Expected nothing to be written, got %q", out.String())
+		}
+
+		if s.Templates.Selected != "" || s.Templates.FuncMap != nil {
+			t.Errorf("Expected the templates to be left untouched, got %+v", s.Templates)
+		}
+	})
+
+	t.Run("with invalid items", func(t *testing.T) {
+		if _, err := RenderSelectPreview(Select{Label: "Pick", Items: "a"}); err == nil {
+			t.Errorf("Expected an error")
+		}
+	})
+}
+
+func TestSelectRunWith(t *testing.T) {
+	stdout := &nopWriteCloser{}
+	s := Select{
+		Label:  "Select Number",
+		Items:  []string{"Zero", "One", "Two"},
+		Stdout: stdout,
+	}
+
+	var wg sync.WaitGroup
+	for i := 0; i < 3; i++ {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+
+			// each run moves down to its own item
+			var out bytes.Buffer
+			idx, _, err := s.RunWith(scriptedStdin(strings.Repeat("j", i)+"\r"), &out)
+			if err != nil {
+				t.Errorf("Unexpected error %v", err)
+				return
+			}
+
+			if idx != i {
+				t.Errorf("Expected item %d, got %d", i, idx)
+			}
+
+			if !strings.Contains(out.String(), "Select Number") {
+				t.Errorf("Expected the select on its own output, got %q", out.String())
+			}
+		}(i)
+	}
+	wg.Wait()
+
+	if s.Templates != nil || s.Keys != nil || s.Stdin != nil {
+		t.Errorf("Expected the select to be left untouched, got %+v", s)
+	}
+
+	if stdout.Len() != 0 {
+		t.Errorf("Expected nothing on the default output, got %q", stdout.String())
+	}
+}
@@ -0,0 +1,296 @@
+package promptui
+
+import (
+	"fmt"
+	"io"
+	"text/template"
+
+	"github.com/chzyer/readline"
+	"github.com/manifoldco/promptui/screenbuf"
+)
+
+// Stepper represents a number to choose within a range. The user increases or decreases the number with the
+// arrow keys, and confirms it by pressing enter. It is an alternative to Select when the choices are a range of
+// numbers too large to list.
+type Stepper struct {
+	// Label is the text displayed before the number to direct input. The IconInitial value "?" will be
+	// prepended automatically to the label so it does not need to be added.
+	//
+	// The value for Label can be a simple string or a struct that will need to be accessed by dot notation
+	// inside the templates. For example, `{{ .Name }}` will display the name property of a struct.
+	Label interface{}
+
+	// Default is the initial number. It is clamped between Min and Max.
+	Default int
+
+	// Min is the lowest number that can be chosen.
+	Min int
+
+	// Max is the highest number that can be chosen. It must be greater or equal than Min.
+	Max int
+
+	// Step is the amount by which the number is increased or decreased. Defaults to 1.
+	Step int
+
+	// PageStep is the amount by which the number is increased or decreased with the page keys. Defaults to 10
+	// times the Step.
+	PageStep int
+
+	// HideHelp sets whether to hide help information.
+	HideHelp bool
+
+	// HideSelected sets whether to hide the text displayed after the number is chosen.
+	HideSelected bool
+
+	// Templates can be used to customize the stepper output. If nil is passed, the
+	// default templates are used. See the StepperTemplates docs for more info.
+	Templates *StepperTemplates
+
+	// Keys is the set of keys used to change the number. See the StepperKeys docs for more info.
+	Keys *StepperKeys
+
+	Stdin  io.ReadCloser
+	Stdout io.WriteCloser
+}
+
+// StepperKeys defines the available keys used by a stepper to change its number.
+type StepperKeys struct {
+	// Increase is the key used to add a Step to the number. Defaults to up arrow key.
+	Increase Key
+
+	// Decrease is the key used to remove a Step from the number. Defaults to down arrow key.
+	Decrease Key
+
+	// PageIncrease is the key used to add a PageStep to the number. Defaults to right arrow key.
+	PageIncrease Key
+
+	// PageDecrease is the key used to remove a PageStep from the number. Defaults to left arrow key.
+	PageDecrease Key
+}
+
+// StepperTemplates allow a stepper to be customized following stdlib text/template syntax. Custom state, colors
+// and background color are available for use inside the templates and are documented inside the Variable
+// section of the docs. See the SelectTemplates docs for examples.
+type StepperTemplates struct {
+	// Label is a text/template for the label displayed before the number. Defaults to printing the label as it
+	// with the IconInitial.
+	Label string
+
+	// Value is a text/template for the number while it is being chosen.
+	Value string
+
+	// Selected is a text/template for the number once it was chosen.
+	Selected string
+
+	// Help is a text/template for displaying instructions at the top. By default it shows the keys used to
+	// change the number.
+	Help string
+
+	// FuncMap is a map of helper functions that can be used inside of templates according to the text/template
+	// documentation.
+	//
+	// By default, FuncMap contains the color functions used to color the text in templates. If FuncMap
+	// is overridden, the colors functions must be added in the override from promptui.FuncMap to work.
+	FuncMap template.FuncMap
+
+	label    *template.Template
+	value    *template.Template
+	selected *template.Template
+	help     *template.Template
+}
+
+// Run executes the stepper. It displays the label and the number, letting the user change it until it is
+// confirmed with enter or the stepper is canceled. It returns the chosen number and an error if any occurred
+// during the stepper's execution.
+func (s *Stepper) Run() (int, error) {
+	if s.Min > s.Max {
+		return 0, fmt.Errorf("stepper min %d must not be greater than max %d", s.Min, s.Max)
+	}
+
+	step := s.Step
+	if step <= 0 {
+		step = 1
+	}
+
+	pageStep := s.PageStep
+	if pageStep <= 0 {
+		pageStep = 10 * step
+	}
+
+	s.setKeys()
+
+	err := s.prepareTemplates()
+	if err != nil {
+		return 0, err
+	}
+
+	c := &readline.Config{
+		Stdin:          translateStdin(s.Stdin),
+		Stdout:         s.Stdout,
+		HistoryLimit:   -1,
+		UniqueEditLine: true,
+	}
+
+	err = c.Init()
+	if err != nil {
+		return 0, err
+	}
+
+	saveTerminal()
+	defer pushTerminalState(c.Stdout, false)()
+
+	rl, err := readline.NewEx(c)
+	if err != nil {
+		return 0, err
+	}
+	defer closeOnPanic(rl)
+
+	rl.Write([]byte(hideCursor))
+	sb := screenbuf.New(rl)
+
+	value := s.clamp(s.Default)
+
+	guard := newLineGuard()
+	c.SetListener(guard.listen(func(line []rune, pos int, key rune) ([]rune, int, bool) {
+		defer restoreOnPanic(rl)
+
+		switch {
+		case key == KeyEnter:
+			return nil, 0, true
+		case s.Keys.Increase.Matches(key):
+			value = s.clamp(value + step)
+		case s.Keys.Decrease.Matches(key):
+			value = s.clamp(value - step)
+		case s.Keys.PageIncrease.Matches(key):
+			value = s.clamp(value + pageStep)
+		case s.Keys.PageDecrease.Matches(key):
+			value = s.clamp(value - pageStep)
+		}
+
+		if !s.HideHelp {
+			sb.Write(render(s.Templates.help, s.Keys))
+		}
+
+		output := render(s.Templates.label, s.Label)
+		output = append(output, render(s.Templates.value, value)...)
+		sb.Write(output)
+
+		sb.Flush()
+
+		return nil, 0, true
+	}))
+
+	_, err = rl.Readline()
+	guard.wait(err)
+
+	if err != nil {
+		switch {
+		case err == readline.ErrInterrupt, err.Error() == "Interrupt":
+			err = ErrInterrupt
+		case err == io.EOF:
+			err = ErrEOF
+		}
+
+		sb.Reset()
+		sb.WriteString("")
+		sb.WriteSequence([]byte(showCursor))
+		sb.Flush()
+		rl.Close()
+		return 0, err
+	}
+
+	sb.WriteSequence([]byte(showCursor))
+	if s.HideSelected {
+		clearScreen(sb)
+	} else {
+		sb.Reset()
+		sb.Write(render(s.Templates.selected, value))
+		sb.Flush()
+	}
+
+	rl.Close()
+
+	return value, nil
+}
+
+func (s *Stepper) clamp(value int) int {
+	if value < s.Min {
+		return s.Min
+	}
+	if value > s.Max {
+		return s.Max
+	}
+	return value
+}
+
+func (s *Stepper) setKeys() {
+	if s.Keys != nil {
+		return
+	}
+	s.Keys = &StepperKeys{
+		Increase:     Key{Code: KeyPrev, Display: KeyPrevDisplay},
+		Decrease:     Key{Code: KeyNext, Display: KeyNextDisplay},
+		PageIncrease: Key{Code: KeyForward, Display: KeyForwardDisplay},
+		PageDecrease: Key{Code: KeyBackward, Display: KeyBackwardDisplay},
+	}
+}
+
+func (s *Stepper) prepareTemplates() error {
+	tpls := s.Templates
+	if tpls == nil {
+		tpls = &StepperTemplates{}
+	}
+
+	if tpls.FuncMap == nil {
+		tpls.FuncMap = FuncMap
+	}
+
+	if tpls.Label == "" {
+		tpls.Label = fmt.Sprintf("%s {{.}}: ", IconInitial)
+	}
+
+	tpl, err := template.New("").Funcs(tpls.FuncMap).Parse(tpls.Label)
+	if err != nil {
+		return err
+	}
+
+	tpls.label = tpl
+
+	if tpls.Value == "" {
+		tpls.Value = "{{ . | underline }}"
+	}
+
+	tpl, err = template.New("").Funcs(tpls.FuncMap).Parse(tpls.Value)
+	if err != nil {
+		return err
+	}
+
+	tpls.value = tpl
+
+	if tpls.Selected == "" {
+		tpls.Selected = fmt.Sprintf(`{{ %q | green }} {{ . | faint }}`, IconGood)
+	}
+
+	tpl, err = template.New("").Funcs(tpls.FuncMap).Parse(tpls.Selected)
+	if err != nil {
+		return err
+	}
+
+	tpls.selected = tpl
+
+	if tpls.Help == "" {
+		tpls.Help = `{{ "Use the arrow keys to change the value:" | faint }} {{ .Increase.HelpText | faint }} ` +
+			`{{ .Decrease.HelpText | faint }} {{ .PageIncrease.HelpText | faint }} {{ .PageDecrease.HelpText | faint }}`
+	}
+
+	tpl, err = template.New("").Funcs(tpls.FuncMap).Parse(tpls.Help)
+	if err != nil {
+		return err
+	}
+
+	tpls.help = tpl
+
+	s.Templates = tpls
+
+	return nil
+}
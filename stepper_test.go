@@ -0,0 +1,115 @@
+package promptui
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestStepper(t *testing.T) {
+	up, down, right, left := "\x1b[A", "\x1b[B", "\x1b[C", "\x1b[D"
+
+	tcs := []struct {
+		scenario string
+		stepper  Stepper
+		keys     string
+		expect   int
+	}{
+		{
+			scenario: "default value",
+			stepper:  Stepper{Default: 5, Min: 0, Max: 10},
+			keys:     "\r",
+			expect:   5,
+		},
+		{
+			scenario: "default value out of range",
+			stepper:  Stepper{Default: 50, Min: 0, Max: 10},
+			keys:     "\r",
+			expect:   10,
+		},
+		{
+			scenario: "increase and decrease",
+			stepper:  Stepper{Default: 5, Min: 0, Max: 10},
+			keys:     up + up + up + down + "\r",
+			expect:   7,
+		},
+		{
+			scenario: "custom step",
+			stepper:  Stepper{Min: 0, Max: 100, Step: 5},
+			keys:     up + up + "\r",
+			expect:   10,
+		},
+		{
+			scenario: "page steps",
+			stepper:  Stepper{Min: 0, Max: 100},
+			keys:     right + right + left + up + "\r",
+			expect:   11,
+		},
+		{
+			scenario: "custom page step",
+			stepper:  Stepper{Min: 0, Max: 100, PageStep: 25},
+			keys:     right + "\r",
+			expect:   25,
+		},
+		{
+			scenario: "clamped to the range",
+			stepper:  Stepper{Default: 1, Min: -2, Max: 3},
+			keys:     right + down + left + down + "\r",
+			expect:   -2,
+		},
+	}
+
+	for _, tc := range tcs {
+		t.Run(tc.scenario, func(t *testing.T) {
+			s := tc.stepper
+			s.Label = "Replicas"
+			s.Stdin = scriptedStdin(tc.keys)
+			s.Stdout = &nopWriteCloser{}
+
+			value, err := s.Run()
+			if err != nil {
+				t.Fatalf("Unexpected error %v", err)
+			}
+
+			if value != tc.expect {
+				t.Errorf("Expected %d, got %d", tc.expect, value)
+			}
+		})
+	}
+
+	t.Run("when min is greater than max", func(t *testing.T) {
+		s := Stepper{Min: 10, Max: 0}
+
+		_, err := s.Run()
+		if err == nil {
+			t.Errorf("Expected error, got none")
+		}
+	})
+
+	t.Run("when using custom templates", func(t *testing.T) {
+		out := &nopWriteCloser{}
+		s := Stepper{
+			Label: "Replicas",
+			Min:   1,
+			Max:   9,
+			Templates: &StepperTemplates{
+				Label:    "{{ . }}: ",
+				Value:    "< {{ . }} >",
+				Selected: "{{ . }} replicas",
+			},
+			HideHelp: true,
+			Stdin:    scriptedStdin("\x1b[A\r"),
+			Stdout:   out,
+		}
+
+		_, err := s.Run()
+		if err != nil {
+			t.Fatalf("Unexpected error %v", err)
+		}
+
+		for _, exp := range []string{"Replicas: < 1 >", "Replicas: < 2 >", "2 replicas"} {
+			if !strings.Contains(out.String(), exp) {
+				t.Errorf("Expected output to contain %q, got %q", exp, out.String())
+			}
+		}
+	})
+}
@@ -18,4 +18,10 @@ var (
 
 	// IconSelect is the icon used to identify the currently selected item in select mode.
 	IconSelect = Styler(FGBold)(">")
+
+	// IconChecked is the icon used to identify the checked items in multi select mode.
+	IconChecked = Styler(FGGreen)("[x]")
+
+	// IconUnchecked is the icon used to identify the unchecked items in multi select mode.
+	IconUnchecked = "[ ]"
 )
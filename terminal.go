@@ -0,0 +1,235 @@
+package promptui
+
+import (
+	"io"
+	"os"
+	"strings"
+	"sync"
+
+	"github.com/chzyer/readline"
+)
+
+// IsTerminal reports whether the given file descriptor is a terminal, such as int(os.Stdin.Fd()).
+func IsTerminal(fd int) bool {
+	return readline.IsTerminal(fd)
+}
+
+// SupportsColor reports whether the standard output is a terminal able to display the colors and styles used by
+// the default templates. It is false when the output is redirected, when the terminal is a dumb one or when the
+// NO_COLOR environment variable is set, following https://no-color.org.
+func SupportsColor() bool {
+	return supportsColor(int(os.Stdout.Fd()), os.Getenv)
+}
+
+func supportsColor(fd int, getenv func(string) string) bool {
+	if getenv("NO_COLOR") != "" || getenv("TERM") == "dumb" {
+		return false
+	}
+	return IsTerminal(fd)
+}
+
+// TerminalSize returns the number of columns and rows of the terminal the standard output is written to. It
+// returns an error when the standard output isn't a terminal.
+func TerminalSize() (cols, rows int, err error) {
+	return terminalSize(int(os.Stdout.Fd()))
+}
+
+func terminalSize(fd int) (cols, rows int, err error) {
+	return readline.GetSize(fd)
+}
+
+// Terminal moves the cursor and clears lines of the terminal, with the same escape sequences the prompts use to
+// redraw themselves. It allows arranging the output of several prompts, for example to clear the lines left by a
+// prompt once the next one has been answered. It should write to the same writer as the prompts, which is the
+// standard output unless their Stdout is set.
+type Terminal struct {
+	w io.Writer
+}
+
+// NewTerminal creates a Terminal writing to w, or to the standard output if w is nil.
+func NewTerminal(w io.Writer) *Terminal {
+	if w == nil {
+		w = readline.Stdout
+	}
+	return &Terminal{w: w}
+}
+
+// CursorUp moves the cursor up by n lines.
+func (t *Terminal) CursorUp(n int) error {
+	if n <= 0 {
+		return nil
+	}
+	return t.write(upLine(uint(n)))
+}
+
+// CursorDown moves the cursor down by n lines.
+func (t *Terminal) CursorDown(n int) error {
+	if n <= 0 {
+		return nil
+	}
+	return t.write(movementCode(uint(n), 'B'))
+}
+
+// ClearLine clears the line of the cursor, moving the cursor to its start.
+func (t *Terminal) ClearLine() error {
+	return t.write(clearLine + "\r")
+}
+
+// ClearLines clears the n lines above the cursor, moving the cursor to the start of the first one. Called with
+// the number of lines left by prompts, it erases them so that the next output takes their place.
+func (t *Terminal) ClearLines(n int) error {
+	if n <= 0 {
+		return nil
+	}
+	return t.write(strings.Repeat(moveUp+clearLine+"\r", n))
+}
+
+// HideCursor hides the cursor of the terminal.
+func (t *Terminal) HideCursor() error {
+	return t.write(hideCursor)
+}
+
+// ShowCursor shows the cursor of the terminal again.
+func (t *Terminal) ShowCursor() error {
+	return t.write(showCursor)
+}
+
+func (t *Terminal) write(seq string) error {
+	_, err := io.WriteString(t.w, seq)
+	return err
+}
+
+// terminalState is the state of the terminal set up by a running prompt or select, on top of the raw mode that
+// readline enters and leaves around each line it reads.
+type terminalState struct {
+	out io.Writer
+
+	// mouse is set while the mouse reporting is on.
+	mouse bool
+}
+
+// terminalStates is the stack of the states set up by the running prompts and selects, the innermost last. The
+// hooks of a select, such as OnSelect, run nested prompts while the select is still running.
+var terminalStates struct {
+	sync.Mutex
+	stack []*terminalState
+}
+
+// pushTerminalState saves the state of the terminal set up by the running prompt or select, if any, before a
+// nested one sets up its own on out. The mouse reporting of the outer one is turned off meanwhile, unless the
+// nested one reports the mouse too. The returned function restores the saved state once the nested one is done:
+// the cursor is hidden again and the mouse reporting turned back on, whatever the nested one left behind.
+func pushTerminalState(out io.Writer, mouse bool) func() {
+	state := &terminalState{out: out, mouse: mouse}
+
+	terminalStates.Lock()
+	var outer *terminalState
+	if n := len(terminalStates.stack); n > 0 {
+		outer = terminalStates.stack[n-1]
+	}
+	terminalStates.stack = append(terminalStates.stack, state)
+	terminalStates.Unlock()
+
+	if outer != nil && outer.mouse && !mouse {
+		outer.out.Write([]byte(mouseOff))
+	}
+
+	return func() {
+		terminalStates.Lock()
+		for i := len(terminalStates.stack) - 1; i >= 0; i-- {
+			if terminalStates.stack[i] == state {
+				terminalStates.stack = append(terminalStates.stack[:i], terminalStates.stack[i+1:]...)
+				break
+			}
+		}
+		terminalStates.Unlock()
+
+		if outer == nil {
+			return
+		}
+
+		restore := hideCursor
+		if outer.mouse {
+			restore += mouseOn
+		}
+		outer.out.Write([]byte(restore))
+	}
+}
+
+// savedTerminals holds the states of the terminals before the prompts put them in raw mode for the first time, by
+// file descriptor, for RestoreTerminal.
+var savedTerminals struct {
+	sync.Mutex
+	states map[int]*readline.State
+}
+
+// saveTerminal saves the state of the terminal readline reads, the first time a prompt runs on it.
+func saveTerminal() {
+	fd := readline.GetStdin()
+	if !IsTerminal(fd) {
+		return
+	}
+
+	savedTerminals.Lock()
+	defer savedTerminals.Unlock()
+
+	if _, ok := savedTerminals.states[fd]; ok {
+		return
+	}
+
+	state, err := readline.GetState(fd)
+	if err != nil {
+		return
+	}
+	if savedTerminals.states == nil {
+		savedTerminals.states = make(map[int]*readline.State)
+	}
+	savedTerminals.states[fd] = state
+}
+
+// RestoreTerminal restores the terminal of the given file descriptor, such as int(os.Stdin.Fd()), to the state it
+// was in before the prompts put it in raw mode for the first time, and shows the cursor on the standard output. It
+// does nothing for a terminal no prompt ran on.
+//
+// The prompts restore the terminal when they return, including when they panic, so RestoreTerminal is a last
+// resort, for example when the program recovers from a panic while a prompt runs in another goroutine:
+//
+//	defer func() {
+//		if r := recover(); r != nil {
+//			promptui.RestoreTerminal(int(os.Stdin.Fd()))
+//			log.Fatal(r)
+//		}
+//	}()
+func RestoreTerminal(fd int) error {
+	savedTerminals.Lock()
+	state := savedTerminals.states[fd]
+	savedTerminals.Unlock()
+
+	if state == nil {
+		return nil
+	}
+
+	readline.Stdout.Write([]byte(showCursor))
+	return readline.Restore(fd, state)
+}
+
+// closeOnPanic closes rl when the goroutine running the prompt panics, which restores the terminal, before letting
+// the panic through to the caller of the prompt.
+func closeOnPanic(rl *readline.Instance) {
+	if r := recover(); r != nil {
+		rl.Write([]byte(showCursor + mouseOff))
+		rl.Close()
+		panic(r)
+	}
+}
+
+// restoreOnPanic restores the terminal when the listener of rl panics, before letting the panic through. The
+// listener runs in a goroutine of readline, where rl can't be closed and the panic can't be recovered by the
+// caller of the prompt, so the program then ends with the terminal as it was.
+func restoreOnPanic(rl *readline.Instance) {
+	if r := recover(); r != nil {
+		rl.Write([]byte(showCursor + mouseOff))
+		rl.Terminal.ExitRawMode()
+		panic(r)
+	}
+}
@@ -0,0 +1,89 @@
+package promptui
+
+import (
+	"bytes"
+	"io/ioutil"
+	"os"
+	"testing"
+)
+
+// notTerminal returns the file descriptor of a file which isn't a terminal.
+func notTerminal(t *testing.T) (int, func()) {
+	f, err := ioutil.TempFile("", "promptui")
+	if err != nil {
+		t.Fatalf("Unexpected error %v", err)
+	}
+	return int(f.Fd()), func() {
+		f.Close()
+		os.Remove(f.Name())
+	}
+}
+
+func TestIsTerminal(t *testing.T) {
+	fd, restore := notTerminal(t)
+	defer restore()
+
+	if IsTerminal(fd) {
+		t.Errorf("Expected a file not to be a terminal")
+	}
+}
+
+func TestRestoreTerminal(t *testing.T) {
+	fd, restore := notTerminal(t)
+	defer restore()
+
+	if err := RestoreTerminal(fd); err != nil {
+		t.Errorf("Expected a terminal no prompt ran on to be left as is, got %v", err)
+	}
+}
+
+func TestSupportsColor(t *testing.T) {
+	fd, restore := notTerminal(t)
+	defer restore()
+
+	env := map[string]string{"TERM": "xterm-256color"}
+	getenv := func(key string) string { return env[key] }
+
+	if supportsColor(fd, getenv) {
+		t.Errorf("Expected colors not to be supported when not writing to a terminal")
+	}
+}
+
+func TestTerminalSize(t *testing.T) {
+	fd, restore := notTerminal(t)
+	defer restore()
+
+	_, _, err := terminalSize(fd)
+	if err == nil {
+		t.Errorf("Expected an error when not writing to a terminal")
+	}
+}
+
+func TestTerminal(t *testing.T) {
+	tcs := []struct {
+		scenario string
+		write    func(*Terminal) error
+		expect   string
+	}{
+		{"cursor up", func(t *Terminal) error { return t.CursorUp(2) }, "\x1b[2A"},
+		{"cursor down", func(t *Terminal) error { return t.CursorDown(3) }, "\x1b[3B"},
+		{"no move", func(t *Terminal) error { return t.CursorUp(0) }, ""},
+		{"clear line", func(t *Terminal) error { return t.ClearLine() }, "\x1b[2K\r"},
+		{"clear lines", func(t *Terminal) error { return t.ClearLines(2) }, "\x1b[1A\x1b[2K\r\x1b[1A\x1b[2K\r"},
+		{"hide cursor", func(t *Terminal) error { return t.HideCursor() }, "\x1b[?25l"},
+		{"show cursor", func(t *Terminal) error { return t.ShowCursor() }, "\x1b[?25h"},
+	}
+
+	for _, tc := range tcs {
+		t.Run(tc.scenario, func(t *testing.T) {
+			var buf bytes.Buffer
+			if err := tc.write(NewTerminal(&buf)); err != nil {
+				t.Fatalf("Unexpected error %v", err)
+			}
+
+			if buf.String() != tc.expect {
+				t.Errorf("Expected %q, got %q", tc.expect, buf.String())
+			}
+		})
+	}
+}
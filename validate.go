@@ -0,0 +1,77 @@
+package promptui
+
+import (
+	"errors"
+	"fmt"
+	"net"
+	"net/mail"
+	"net/url"
+	"regexp"
+	"strings"
+)
+
+// Required is a ValidateFunc refusing an empty input, or one made only of whitespace.
+func Required(input string) error {
+	return RequiredWith("a value is required")(input)
+}
+
+// RequiredWith returns a ValidateFunc like Required, which refuses an empty input with the given message instead
+// of the default one.
+func RequiredWith(msg string) ValidateFunc {
+	return func(input string) error {
+		if strings.TrimSpace(input) == "" {
+			return errors.New(msg)
+		}
+		return nil
+	}
+}
+
+// ValidateEmail returns a ValidateFunc accepting a single email address, such as "gopher@example.com", without
+// a display name or angle brackets.
+func ValidateEmail() ValidateFunc {
+	return func(input string) error {
+		addr, err := mail.ParseAddress(input)
+		if err != nil || addr.Address != input {
+			return fmt.Errorf("%q is not a valid email address", input)
+		}
+		return nil
+	}
+}
+
+// ValidateURL returns a ValidateFunc accepting an absolute URL with a host, such as "https://example.com/path".
+// If schemes are given, the URL must use one of them, for example
+//
+//	ValidateURL("http", "https")
+func ValidateURL(schemes ...string) ValidateFunc {
+	return func(input string) error {
+		u, err := url.Parse(input)
+		if err != nil || u.Scheme == "" || u.Host == "" {
+			return fmt.Errorf("%q is not a valid URL", input)
+		}
+
+		if len(schemes) == 0 {
+			return nil
+		}
+		for _, scheme := range schemes {
+			if strings.EqualFold(u.Scheme, scheme) {
+				return nil
+			}
+		}
+		return fmt.Errorf("URL must start with %s://", strings.Join(schemes, ":// or "))
+	}
+}
+
+var hostnameRegexp = regexp.MustCompile(`(?i)^[a-z0-9]([a-z0-9-]{0,61}[a-z0-9])?(\.[a-z0-9]([a-z0-9-]{0,61}[a-z0-9])?)*\.?$`)
+
+// ValidateHost returns a ValidateFunc accepting a hostname, such as "example.com", or an IP address.
+func ValidateHost() ValidateFunc {
+	return func(input string) error {
+		if net.ParseIP(input) != nil {
+			return nil
+		}
+		if len(input) > 253 || !hostnameRegexp.MatchString(input) {
+			return fmt.Errorf("%q is not a valid host", input)
+		}
+		return nil
+	}
+}
@@ -0,0 +1,81 @@
+package promptui
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestRequired(t *testing.T) {
+	cases := []struct {
+		input string
+		valid bool
+	}{
+		{input: "gopher", valid: true},
+		{input: " gopher ", valid: true},
+		{input: "", valid: false},
+		{input: " \t ", valid: false},
+	}
+
+	for _, tc := range cases {
+		err := Required(tc.input)
+		if tc.valid && err != nil {
+			t.Errorf("Expected %q to be valid, got %v", tc.input, err)
+		}
+		if !tc.valid && err == nil {
+			t.Errorf("Expected %q to be invalid", tc.input)
+		}
+	}
+}
+
+func TestRequiredWith(t *testing.T) {
+	err := RequiredWith("a name is required")("  ")
+	if err == nil || err.Error() != "a name is required" {
+		t.Errorf("Expected the custom message, got %v", err)
+	}
+}
+
+func testValidateFunc(t *testing.T, validate ValidateFunc, valid, invalid []string) {
+	for _, input := range valid {
+		if err := validate(input); err != nil {
+			t.Errorf("Expected %q to be valid, got %v", input, err)
+		}
+	}
+
+	for _, input := range invalid {
+		if err := validate(input); err == nil {
+			t.Errorf("Expected %q to be invalid", input)
+		}
+	}
+}
+
+func TestValidateEmail(t *testing.T) {
+	valid := []string{"gopher@example.com", "first.last+tag@sub.example.org"}
+	invalid := []string{"", "gopher", "gopher@", "@example.com", "Gopher <gopher@example.com>", "a@b@c"}
+	testValidateFunc(t, ValidateEmail(), valid, invalid)
+}
+
+func TestValidateURL(t *testing.T) {
+	t.Run("any scheme", func(t *testing.T) {
+		valid := []string{"https://example.com", "ftp://example.com/file", "http://localhost:8080/path?q=1"}
+		invalid := []string{"", "example.com", "/path", "https://", "http://exa mple.com"}
+		testValidateFunc(t, ValidateURL(), valid, invalid)
+	})
+
+	t.Run("given schemes", func(t *testing.T) {
+		valid := []string{"https://example.com", "HTTP://example.com"}
+		invalid := []string{"ftp://example.com"}
+		testValidateFunc(t, ValidateURL("http", "https"), valid, invalid)
+
+		exp := "URL must start with http:// or https://"
+		if err := ValidateURL("http", "https")("ftp://example.com"); err == nil || err.Error() != exp {
+			t.Errorf("Expected %q, got %v", exp, err)
+		}
+	})
+}
+
+func TestValidateHost(t *testing.T) {
+	valid := []string{"localhost", "example.com", "sub-domain.example.com.", "127.0.0.1", "::1"}
+	invalid := []string{"", "-example.com", "example-.com", "exa_mple.com", "example..com", "https://example.com",
+		strings.Repeat("a", 64) + ".com"}
+	testValidateFunc(t, ValidateHost(), valid, invalid)
+}